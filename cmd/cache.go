@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// openCache returns the result cache for this run with the puzzle's key and
+// configuration, or a nil cache when caching is off or cannot apply: --all and
+// --size answer other questions, logged and checkpointed runs must actually
+// search, and restarts without --seed never repeat.
+func openCache(cfg *config, pieces []*internal.Tetromino, opts internal.Options) (*internal.Cache, string, string) {
+	if cfg.noCache || cfg.all || cfg.size > 0 || cfg.searchLog != "" || cfg.checkpoint != "" || cfg.resume != "" || // Nothing to look up, or nothing worth storing
+		(cfg.restarts && !cfg.seedSet) {
+		return nil, "", ""
+	}
+	dir := cfg.cacheDir
+	if dir == "" { // No --cache-dir: use the user cache directory
+		var err error
+		if dir, err = internal.DefaultCacheDir(); err != nil { // No home directory: run uncached
+			return nil, "", ""
+		}
+	}
+
+	config := opts.String() // Cache entries are per configuration
+	if cfg.portfolio {      // Portfolio winners differ by strategy
+		config = "portfolio" // Any strategy's board will do
+	}
+	return &internal.Cache{Dir: dir}, internal.CacheKey(pieces, config), config
+}
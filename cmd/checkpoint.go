@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// saveCheckpoint writes the checkpoint next to filename and renames it into
+// place, so a crash mid-write never leaves a truncated checkpoint behind.
+func saveCheckpoint(filename string, cp *internal.Checkpoint) error {
+	tmp := filename + ".tmp" // Write beside the target, then rename over it
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = internal.WriteCheckpoint(f, cp)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil { // Write or close failed: drop the partial file
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename) // Atomic on the same filesystem
+}
+
+// loadCheckpoint reads a checkpoint and checks it against the puzzle.
+func loadCheckpoint(filename string, pieces []*internal.Tetromino) (*internal.Checkpoint, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // Ensure file is closed on exit
+	return internal.ReadCheckpoint(f, pieces)
+}
@@ -2,10 +2,16 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,16 +22,146 @@ func main() {
 	os.Exit(run()) // Wrap in run() so defers execute before exit
 }
 
+// usage is the command line synopsis shown on argument errors.
+const usage = `Usage: tetris-optimizer [options] <input-file>
+       tetris-optimizer verify [--minimal] <puzzle-file> <solution-file>
+       tetris-optimizer check-log <puzzle-file> <search-log>
+       tetris-optimizer cnf [--size N] <puzzle-file>
+       tetris-optimizer from-sat [--size N] <puzzle-file> <solver-output>
+       tetris-optimizer render [options] <solution-file>
+       tetris-optimizer replay [options] <puzzle-file> <search-log>`
+
+// searches maps --search values to size search orders.
+var searches = map[string]internal.SizeSearch{
+	"ascending":  internal.Ascending,
+	"descending": internal.Descending,
+	"bisect":     internal.Bisect,
+}
+
+// orders maps --order values to piece orderings.
+var orders = map[string]internal.PieceOrder{
+	"input":       internal.InputOrder,
+	"constrained": internal.ConstrainedFirst,
+	"dynamic":     internal.FewestPlacements,
+	"bbox":        internal.LargestBox,
+}
+
+// portfolioSeeds is how many randomized-restart strategies --portfolio races.
+const portfolioSeeds = 2
+
+// heartbeatTicks is how many progress ticks (100ms each) pass between
+// --progress=json heartbeats.
+const heartbeatTicks = 10
+
+// config holds the parsed command line options.
+type config struct {
+	filename   string      // Input file with tetrominoes
+	all        bool        // Print every solution at the minimal size
+	unique     bool        // With all, drop solutions equivalent under symmetry
+	canonical  bool        // Pick (or order) solutions by their rendered text
+	stats      bool        // Report per-size outcomes on stderr
+	live       bool        // Draw the partial board while solving on a terminal
+	timings    timingsFlag // Phase timings on stderr: "table", "json" or "" for none
+	progress   string      // Progress reporting: "bar", "json" or "none"
+	progressFD int         // File descriptor for --progress=json events
+	size       int         // Try only this board size (0: search for the minimum)
+	searchLog  string      // File to write the search log to
+	backend    string      // Search backend: "backtrack" or "sat"
+	anytime    bool        // Shorthand for search=descending
+	search     string      // Size search order: "ascending", "descending" or "bisect"
+	order      string      // Piece order for the backtracker, a key of orders
+	restarts   bool        // Randomized backtracking with restarts
+	seed       int64       // Seed for restarts
+	seedSet    bool        // Whether --seed was given; otherwise one is picked and reported
+	portfolio  bool        // Race several configurations, keep the first proven answer
+	noCache    bool        // Neither read nor write the result cache
+	cacheDir   string      // Result cache directory (empty: the user cache directory)
+	color      string      // Colour boards on stdout: "auto", "always" or "never"
+	format     string      // Board rendering, a key of formats
+	image      imageFlags  // Options of the svg and png formats
+
+	checkpoint      string        // File to save the search frontier to
+	checkpointEvery time.Duration // Interval between periodic checkpoints
+	resume          string        // Checkpoint file to continue from
+
+	cpuProfile string // File to write a CPU profile of the solve to
+	memProfile string // File to write a heap profile to after the solve
+	traceFile  string // File to write an execution trace of the solve to
+}
+
+// timingsFlag is the --timings value. It is a boolean flag that also takes a
+// format, so plain --timings means --timings=table.
+type timingsFlag string
+
+func (f *timingsFlag) String() string   { return string(*f) }
+func (f *timingsFlag) IsBoolFlag() bool { return true }
+
+func (f *timingsFlag) Set(s string) error {
+	switch s { // Value after --timings=, or "true" when bare
+	case "true", "table":
+		*f = "table"
+	case "false":
+		*f = ""
+	case "json":
+		*f = "json"
+	default:
+		return fmt.Errorf("unknown timings format %q (want table or json)", s)
+	}
+	return nil
+}
+
 func run() int {
-	filename, err := parseArgs(os.Args[1:])
+	if len(os.Args) > 1 { // Subcommands
+		switch os.Args[1] {
+		case "verify": // Check an existing solution
+			return runVerify(os.Args[2:])
+		case "check-log": // Check a search log
+			return runCheckLog(os.Args[2:])
+		case "cnf": // Export the SAT encoding
+			return runCNF(os.Args[2:])
+		case "from-sat": // Import a SAT solver's model
+			return runFromSAT(os.Args[2:])
+		case "render": // Draw an existing solution
+			return runRender(os.Args[2:])
+		case "replay": // Play back a search log
+			return runReplay(os.Args[2:])
+		}
+	}
+
+	cfg, err := parseArgs(os.Args[1:])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
+	if err := checkBinaryOutput(cfg.format); err != nil { // PNG to a terminal
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	status, noBoard := os.Stdout, 0 // Where TIMEOUT and the like go, and the exit code when no board is drawn
+	if imageFormat(cfg.format) {    // Text would corrupt the image; an empty one must not look like success
+		status, noBoard = os.Stderr, 1
+	}
 
 	tmr := internal.NewTimer()                                                 // Initialize timer for progress display
 	ctx, cancel := context.WithTimeout(context.Background(), internal.Timeout) // 5-minute timeout from spec
 	defer cancel()                                                             // Ensure context is cancelled on exit
+	if cfg.timings != "" {                                                     // Timings asked for
+		defer printTimings(tmr, string(cfg.timings)) // Covers every way out below
+	}
+	var events *internal.EventWriter // --progress=json stream
+	if cfg.progress == "json" {      // Open the output before anything can fail
+		out, err := progressOutput(cfg.progressFD)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		events = internal.NewEventWriter(out) // One JSON object per line
+		defer func() {                        // Report a failed write once, at exit
+			if err := events.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "writing progress events: %v\n", err)
+			}
+		}()
+	}
 
 	sigChan := make(chan os.Signal, 1)                    // Buffer of 1 ensures signal delivery even if not immediately received
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM) // Register for Ctrl+C and kill signals
@@ -40,68 +176,456 @@ func run() int {
 		}
 	}()
 
-	parseStart := time.Now()                         // Start timing parse phase
-	pieces, parseErr := internal.ParseFile(filename) // Parse and validate input file
-	tmr.AddDuration("Parse", time.Since(parseStart)) // Record parse duration
+	parseStart := time.Now()                             // Start timing parse phase
+	pieces, parseErr := internal.ParseFile(cfg.filename) // Parse and validate input file
+	tmr.AddDuration("parse", time.Since(parseStart))     // Record parse duration
 
 	if parseErr != nil {
-		fmt.Println("ERROR") // Spec requires "ERROR" on stdout for invalid input
+		fmt.Fprintln(status, "ERROR") // Spec requires "ERROR" on stdout for invalid input
 		fmt.Fprintln(os.Stderr, parseErr)
-		return 0 // Exit 0 per spec; error is communicated via stdout message
+		if events != nil { // Stream consumers learn why the run ended
+			events.Write(internal.Event{Event: "finished", Outcome: "error", Error: parseErr.Error()})
+		}
+		return noBoard // Exit 0 per spec for text; error is communicated via the status message
+	}
+	if events != nil { // Pieces parsed: the run is on
+		events.Write(internal.Event{Event: "started", Pieces: len(pieces)})
+	}
+
+	var progress *internal.Progress                                                          // Search state behind the progress bar or live display
+	liveColor := cfg.color == "always" || cfg.color == "auto" && os.Getenv("NO_COLOR") == "" // Live boards follow --color like stdout
+	if !cfg.all && !cfg.portfolio {                                                          // Neither reports progress; the bar counts down to the timeout
+		progress = &internal.Progress{}
 	}
 
 	progressDone := make(chan struct{}) // Signals progress goroutine completion
 	go func() {                         // Background goroutine for progress display
-		defer close(progressDone)                        // Signal main when done
-		ticker := time.NewTicker(100 * time.Millisecond) // Update progress bar 10 times per second
-		defer ticker.Stop()                              // Clean up ticker on exit
+		defer close(progressDone)                             // Signal main when done
+		ticker := time.NewTicker(100 * time.Millisecond)      // Update progress bar 10 times per second
+		defer ticker.Stop()                                   // Clean up ticker on exit
+		ticks, lastTotal, lastBeat := 0, int64(0), time.Now() // Nodes and time at the last heartbeat
 		for {
 			select {
 			case <-ticker.C: // Ticker fired
-				tmr.ShowProgress() // Update progress bar
+				switch {
+				case events != nil: // Heartbeat every heartbeatTicks ticks
+					if ticks++; ticks%heartbeatTicks == 0 { // Once a second
+						snap := progress.Snapshot()                                            // Statistics so far
+						rate := float64(snap.Total-lastTotal) / time.Since(lastBeat).Seconds() // Nodes per second since the last heartbeat
+						lastTotal, lastBeat = snap.Total, time.Now()
+						events.Write(internal.HeartbeatEvent(snap, rate)) // One line of JSON
+					}
+				case cfg.progress == "none": // Quiet: draw nothing
+				case cfg.live: // Partial board instead of the bar
+					tmr.ShowLive(progress.Snapshot(), liveColor) // Redraw the partial board
+				default:
+					tmr.ShowProgress(progress.Snapshot()) // Update progress bar
+				}
 			case <-ctx.Done(): // Solve completed or cancelled
 				return
 			}
 		}
 	}()
 
-	solveStart := time.Now()                      // Start timing solve phase
-	result := internal.Solve(ctx, pieces)         // Run backtracking solver
-	solveDuration := time.Since(solveStart)       // Calculate solve duration
-	tmr.AddDuration("Total solve", solveDuration) // Record solve duration
+	opts := internal.Options{Canonical: cfg.canonical, Search: searches[cfg.search], Order: orders[cfg.order], Progress: progress} // Options shared by every search
+	if cfg.restarts {                                                                                                              // Randomized restarts
+		if !cfg.seedSet { // No --seed: pick one and report it
+			cfg.seed = time.Now().UnixNano()
+		}
+		fmt.Fprintf(os.Stderr, "seed: %d\n", cfg.seed) // Rerun with --seed to reproduce
+		opts.Restarts, opts.Seed = true, cfg.seed
+	}
+	if cfg.backend == "sat" { // CDCL backend
+		opts.Backend = internal.SAT
+	}
+	if events != nil { // Report each size on the event stream
+		sizeHooks(&opts, events)
+	}
+	if cfg.resume != "" { // Continue from a saved frontier
+		if opts.Resume, err = loadCheckpoint(cfg.resume, pieces); err != nil { // Bad or mismatched checkpoint
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if cfg.checkpoint != "" { // Save the frontier when stopped or periodically
+		var saveErr error                          // Reported once, after solving
+		opts.CheckpointEvery = cfg.checkpointEvery // Zero: only when stopped
+		opts.OnCheckpoint = func(cp *internal.Checkpoint) {
+			if err := saveCheckpoint(cfg.checkpoint, cp); err != nil && saveErr == nil { // Keep going; a missed checkpoint costs nothing now
+				saveErr = err
+				fmt.Fprintf(os.Stderr, "writing checkpoint: %v\n", err)
+			}
+		}
+	}
+	var logFile *os.File
+	var logBuf *bufio.Writer
+	if cfg.searchLog != "" { // Write every node to a file
+		if logFile, err = os.Create(cfg.searchLog); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		logBuf = bufio.NewWriter(logFile)                          // One write per node; buffer them
+		fmt.Fprintf(logBuf, "# search log for %s\n", cfg.filename) // Which puzzle the log belongs to
+		opts.Log = logBuf                                          // Solver writes through the buffer
+	}
+
+	cache, cacheKey, cacheConfig := openCache(cfg, pieces, opts) // Nil when caching does not apply
+	var result *internal.Result
+	if cache != nil { // Try the cache before searching
+		cacheStart := time.Now()
+		if result, err = cache.Get(cacheKey, pieces); err != nil { // Bad entry: solve again and replace it
+			fmt.Fprintf(os.Stderr, "ignoring cached result: %v\n", err)
+		}
+		tmr.AddDuration("cache", time.Since(cacheStart))
+	}
+	cached := result != nil // Cached answers skip the search
+
+	draw := renderer(cfg.format, cfg.color, cfg.image) // Renderer for --format
+	render := func(b *internal.Board) string {         // draw, timed
+		renderStart := time.Now()
+		defer func() { tmr.AddDuration("render", time.Since(renderStart)) }() // Time spent drawing
+		return draw(b)
+	}
+
+	prof, err := startProfiles(cfg) // CPU, heap and trace profiles around the solve
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if logFile != nil { // Nothing was searched; leave no empty log behind
+			logFile.Close()
+			os.Remove(cfg.searchLog)
+		}
+		return 1
+	}
+
+	solveStart := time.Now() // Start timing solve phase
+	switch {
+	case cached: // Verified by Get; nothing to search
+	case cfg.all && cfg.unique: // Every minimal solution, one per symmetry class
+		result = internal.SolveAllUnique(ctx, pieces) // Enumerate, keeping one board per symmetry class
+	case cfg.all: // Every minimal solution
+		result = internal.SolveAll(ctx, pieces) // Enumerate every minimal solution
+	case cfg.size > 0: // Single size
+		result = internal.SolveSize(ctx, pieces, cfg.size, opts) // Try only the requested size
+	case cfg.portfolio: // Several configurations at once
+		result = internal.SolvePortfolio(ctx, pieces, internal.DefaultPortfolio(portfolioSeeds)) // Race the default strategies
+	default: // Search sizes in order
+		result = internal.SolveWith(ctx, pieces, opts) // Run backtracking solver
+	}
+	solveDuration := time.Since(solveStart) // Calculate solve duration
+	if err := prof.stop(); err != nil {     // Stop profiling before any output
+		fmt.Fprintf(os.Stderr, "writing profile: %v\n", err)
+	}
+	if !cached { // Per-size phases; a cached result's are not this run's
+		sizes := result.Sizes   // Every size this run tried
+		if opts.Resume != nil { // Sizes finished before the checkpoint was taken
+			sizes = sizes[min(len(opts.Resume.Done), len(sizes)):]
+		}
+		addSizePhases(tmr, sizes) // Bound checks and searches as timing phases
+	}
+
+	cancel()                   // Stop the context to terminate progress goroutine
+	<-progressDone             // Wait for progress goroutine to finish
+	if cfg.progress == "bar" { // Nothing drawn otherwise
+		tmr.ClearProgress() // Clear progress bar from terminal
+	}
+
+	if logBuf != nil { // Search log was written
+		err := logBuf.Flush() // Push buffered nodes to the file
+		if closeErr := logFile.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil { // Flush or close failed: the log is incomplete
+			fmt.Fprintf(os.Stderr, "writing search log: %v\n", err)
+		}
+	}
+
+	if cache != nil && !cached && !result.Timeout && result.Err == nil && result.Board != nil { // Proven answer: remember it
+		cacheStart := time.Now()
+		if err := cache.Put(cacheKey, cacheConfig, pieces, result); err != nil { // Failing to cache is not fatal
+			fmt.Fprintf(os.Stderr, "writing cache: %v\n", err)
+		}
+		tmr.AddDuration("cache", time.Since(cacheStart))
+	}
+
+	if cfg.stats && cached { // Say where the answer came from
+		fmt.Fprintf(os.Stderr, "cached result %s\n", cacheKey)
+	}
+	if cfg.stats || cfg.size > 0 { // Fixed-size mode always explains its answer
+		printStats(os.Stderr, result)
+	}
 
-	cancel()            // Stop the context to terminate progress goroutine
-	<-progressDone      // Wait for progress goroutine to finish
-	tmr.ClearProgress() // Clear progress bar from terminal
+	if cfg.checkpoint != "" && result.Checkpoint != nil { // Stopped early; say how to continue
+		fmt.Fprintf(os.Stderr, "checkpoint saved to %s (continue with --resume %s)\n", cfg.checkpoint, cfg.checkpoint)
+	}
+	if events != nil { // Last event of the stream
+		wasInterrupted := false
+		select { // Non-blocking check
+		case <-interrupted: // User pressed Ctrl+C or sent SIGTERM
+			wasInterrupted = true
+		default: // Finished or timed out
+		}
+		events.Write(finishedEvent(result, cached, wasInterrupted))
+	}
 
 	select {
 	case <-interrupted: // Check if user interrupted (non-blocking)
-		fmt.Println("INTERRUPTED")
-		return 0
+		if cfg.search != "ascending" && result.Board != nil { // Best board so far is still worth printing
+			fmt.Fprintln(status, "INTERRUPTED - best found (not proven minimal):")
+			fmt.Print(render(result.Board))
+			return 0
+		}
+		fmt.Fprintln(status, "INTERRUPTED")
+		return noBoard
 	default: // Not interrupted, continue
 	}
 
-	if result.Timeout || result.Board == nil { // Solver didn't find solution in time
-		fmt.Println("TIMEOUT - try with fewer tetrominoes")
+	if result.Err != nil { // A size's search broke down; nothing can be claimed
+		fmt.Fprintln(os.Stderr, result.Err)
+		return 1
+	}
+
+	if cfg.size > 0 && !result.Timeout && result.Board == nil { // Size proven infeasible; reason is on stderr
+		fmt.Fprintln(status, "NO SOLUTION")
+		return noBoard
+	}
+
+	if result.Timeout && result.Board != nil { // Bounded search: best board so far, larger than necessary perhaps
+		fmt.Fprintln(status, "TIMEOUT - best found (not proven minimal):")
+		fmt.Print(render(result.Board))
 		return 0
 	}
 
-	fmt.Print(result.Board.String())  // Output solution grid to stdout
-	tmr.ShowCompletion(solveDuration) // Show "Solved in X.XXs" if TTY
+	if result.Timeout || result.Board == nil { // Solver didn't find solution in time
+		fmt.Fprintln(status, "TIMEOUT - try with fewer tetrominoes")
+		return noBoard
+	}
+
+	if cfg.all { // Print every solution
+		solutions := result.Solutions
+		if cfg.canonical { // Search order depends on the piece order
+			sort.Slice(solutions, func(i, j int) bool { // Stable listing independent of search order
+				return solutions[i].String() < solutions[j].String()
+			})
+		}
+		for i, b := range solutions { // Solutions in order
+			if i > 0 { // Not the first
+				fmt.Println() // Blank line between solutions
+			}
+			fmt.Print(render(b))
+		}
+	} else {
+		fmt.Print(render(result.Board)) // Output solution grid to stdout
+	}
+	if cfg.progress == "bar" { // Bar mode only
+		tmr.ShowCompletion(solveDuration) // Show "Solved in X.XXs" if TTY
+	}
 
 	return 0
 }
 
+// addSizePhases records the bound checks and, unless they ruled it out, the
+// search of each size as phases.
+func addSizePhases(tmr *internal.Timer, sizes []internal.SizeReport) {
+	for _, r := range sizes { // One or two phases per size
+		tmr.AddPhase(internal.Phase{Name: "bounds", Size: r.Size, Duration: r.BoundsTime}) // Bounds are checked for every size
+		if r.Outcome != internal.RuledOut {                                                // Searched after the bounds
+			tmr.AddPhase(internal.Phase{Name: "search", Size: r.Size, Duration: r.SearchTime})
+		}
+	}
+}
+
+// printTimings writes the phase timings to stderr as a table or, for format
+// "json", one line of JSON.
+func printTimings(tmr *internal.Timer, format string) {
+	write := tmr.WriteTimings
+	if format == "json" { // One line of JSON
+		write = tmr.WriteTimingsJSON
+	}
+	if err := write(os.Stderr); err != nil { // Stderr may be closed
+		fmt.Fprintf(os.Stderr, "writing timings: %v\n", err)
+	}
+}
+
+// printStats writes what happened at each board size. Sizes without a solution
+// carry their certificate: the bound that ruled them out, or the node count of
+// the exhaustive search.
+func printStats(w io.Writer, result *internal.Result) {
+	if result.Strategy != "" { // Portfolio run: which configuration won
+		fmt.Fprintf(w, "strategy: %s\n", result.Strategy)
+	}
+	for _, r := range result.Sizes { // One line per size tried
+		if r.Bound != nil { // Ruled out without searching
+			fmt.Fprintf(w, "size %d: %s (%s)\n", r.Size, r.Outcome, r.Bound)
+			continue
+		}
+		fmt.Fprintf(w, "size %d: %s after %d nodes\n", r.Size, r.Outcome, r.Nodes)
+	}
+}
+
 // parseArgs parses command line arguments.
-// Returns the input filename and any error.
-func parseArgs(args []string) (string, error) {
-	if len(args) == 0 {
-		return "", fmt.Errorf("Usage: tetris-optimizer <input-file>")
+// Flags may appear before or after the input filename.
+func parseArgs(args []string) (*config, error) {
+	cfg := &config{}                                                // Defaults are set by the flag definitions
+	fs := flag.NewFlagSet("tetris-optimizer", flag.ContinueOnError) // Own flag set, so parseArgs can run more than once
+	fs.SetOutput(io.Discard)                                        // Errors are reported by the caller
+	fs.BoolVar(&cfg.all, "all", false, "print every solution at the minimal size")
+	fs.BoolVar(&cfg.unique, "unique", false, "with --all, print only solutions distinct up to symmetry")
+	fs.BoolVar(&cfg.stats, "stats", false, "report the outcome of each board size on stderr")
+	fs.Var(&cfg.timings, "timings", "on exit, print the time spent parsing, checking bounds, searching each size and rendering on stderr (--timings=json for JSON)")
+	fs.StringVar(&cfg.progress, "progress", "bar", "progress `mode`: bar (on a terminal), json (newline-delimited JSON events) or none")
+	fs.IntVar(&cfg.progressFD, "progress-fd", 2, "with --progress=json, write events to this file descriptor")
+	fs.BoolVar(&cfg.live, "live", false, "while solving, draw the partial board, size, depth and node rate on stderr (terminal only)")
+	fs.BoolVar(&cfg.canonical, "canonical", false, "print the lexicographically smallest minimal solution (with --all: sort solutions)")
+	fs.IntVar(&cfg.size, "size", 0, "try only this board size and explain the answer on stderr")
+	fs.StringVar(&cfg.searchLog, "search-log", "", "write a replayable search log to `file` (see check-log)")
+	fs.StringVar(&cfg.checkpoint, "checkpoint", "", "save the search position to `file` periodically and when stopped")
+	fs.DurationVar(&cfg.checkpointEvery, "checkpoint-every", time.Minute, "interval between periodic checkpoints")
+	fs.StringVar(&cfg.resume, "resume", "", "continue the search saved in checkpoint `file`")
+	fs.BoolVar(&cfg.anytime, "anytime", false, "shorthand for --search=descending")
+	fs.StringVar(&cfg.search, "search", "ascending", "size search `order`: ascending, descending or bisect (the latter two print the best board found on timeout)")
+	fs.StringVar(&cfg.order, "order", "input", "piece `order` for the backtracker: input, constrained, dynamic or bbox")
+	fs.BoolVar(&cfg.restarts, "restarts", false, "randomized backtracking with restarts (prints the seed on stderr)")
+	fs.Int64Var(&cfg.seed, "seed", 0, "seed for --restarts, to reproduce a run")
+	fs.StringVar(&cfg.backend, "backend", "backtrack", "search `backend`: backtrack or sat")
+	fs.BoolVar(&cfg.portfolio, "portfolio", false, "race several solver configurations and keep the first proven answer")
+	fs.StringVar(&cfg.format, "format", "text", "board `format`: text (letters), box (outlined with box-drawing characters), svg or png")
+	cfg.image.register(fs) // --labels, --grid, --cell-size, --border, --palette
+	fs.StringVar(&cfg.color, "color", "auto", "colour pieces on stdout: auto (terminal and no NO_COLOR), always or never")
+	fs.StringVar(&cfg.cpuProfile, "cpuprofile", "", "write a CPU profile of the solve to `file` (see go tool pprof)")
+	fs.StringVar(&cfg.memProfile, "memprofile", "", "write a heap profile taken after the solve to `file`")
+	fs.StringVar(&cfg.traceFile, "trace", "", "write an execution trace of the solve to `file` (see go tool trace)")
+	fs.BoolVar(&cfg.noCache, "no-cache", false, "neither read nor write the result cache")
+	fs.StringVar(&cfg.cacheDir, "cache-dir", "", "keep cached results in `dir` (default: the user cache directory)")
+
+	var positional []string // Non-flag arguments, in order
+	for {                   // Parse flags, set aside a positional, repeat
+		if err := fs.Parse(args); err != nil { // Unknown flag, bad value or -h
+			return nil, usageError(fs, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 { // Everything consumed
+			break
+		}
+		positional = append(positional, args[0]) // flag stops at the first non-flag; resume after it
+		args = args[1:]
 	}
 
-	if len(args) > 1 {
-		return "", fmt.Errorf("Usage: tetris-optimizer <input-file>")
+	if len(positional) != 1 { // Exactly one input file expected
+		return nil, usageError(fs, nil)
 	}
+	fs.Visit(func(f *flag.Flag) { cfg.seedSet = cfg.seedSet || f.Name == "seed" }) // Zero is a valid seed, so ask whether it was given
+	if cfg.seedSet && !cfg.restarts {                                              // A seed without restarts would be ignored
+		return nil, usageError(fs, errors.New("--seed requires --restarts"))
+	}
+	if cfg.restarts && (cfg.all || cfg.canonical || cfg.backend != "backtrack" || cfg.order != "input") { // Restarts pick their own order
+		return nil, usageError(fs, errors.New("--restarts cannot be combined with --all, --canonical, --backend or --order"))
+	}
+	if cfg.restarts && (cfg.searchLog != "" || cfg.checkpoint != "" || cfg.resume != "") { // Randomized runs cannot be replayed or resumed
+		return nil, usageError(fs, errors.New("--restarts searches cannot be logged or checkpointed"))
+	}
+	if cfg.portfolio && (cfg.all || cfg.size > 0 || cfg.canonical || cfg.search != "ascending" || cfg.anytime || // Portfolio picks its own options
+		cfg.backend != "backtrack" || cfg.order != "input" || cfg.restarts) {
+		return nil, usageError(fs, errors.New("--portfolio picks its own configurations; it cannot be combined with other search options"))
+	}
+	if cfg.portfolio && (cfg.searchLog != "" || cfg.checkpoint != "" || cfg.resume != "") { // Strategies race; none of them can be logged
+		return nil, usageError(fs, errors.New("--portfolio searches cannot be logged or checkpointed"))
+	}
+	if cfg.progress != "bar" && cfg.progress != "json" && cfg.progress != "none" { // Unknown progress mode
+		return nil, usageError(fs, fmt.Errorf("unknown --progress mode %q", cfg.progress))
+	}
+	if cfg.live && cfg.progress != "bar" { // Live display draws on the bar's terminal
+		return nil, usageError(fs, errors.New("--live replaces the progress bar; it cannot be combined with --progress="+cfg.progress))
+	}
+	if cfg.progressFD != 2 && cfg.progress != "json" { // Descriptor for nothing
+		return nil, usageError(fs, errors.New("--progress-fd requires --progress=json"))
+	}
+	if cfg.progressFD < 2 { // stdin, or stdout, which carries the board
+		return nil, usageError(fs, errors.New("--progress-fd must be 2 (stderr) or a descriptor opened for the events"))
+	}
+	if cfg.live && (cfg.all || cfg.portfolio) { // Neither reports search progress
+		return nil, usageError(fs, errors.New("--live cannot be combined with --all or --portfolio"))
+	}
+	if cfg.color != "auto" && cfg.color != "always" && cfg.color != "never" { // Unknown colour mode
+		return nil, usageError(fs, fmt.Errorf("unknown --color mode %q", cfg.color))
+	}
+	if !formats[cfg.format] { // Unknown format
+		return nil, usageError(fs, fmt.Errorf("unknown format %q", cfg.format))
+	}
+	if cfg.color == "always" && cfg.format != "text" { // Images have their own palettes
+		return nil, usageError(fs, errors.New("--color applies to --format=text only"))
+	}
+	if err := cfg.image.check(fs, cfg.format); err != nil { // Image flags need an image format
+		return nil, usageError(fs, err)
+	}
+	if cfg.all && (cfg.format == "svg" || cfg.format == "png") { // Images draw a single board
+		return nil, usageError(fs, fmt.Errorf("--format=%s draws one board; it cannot be combined with --all", cfg.format))
+	}
+	if cfg.noCache && cfg.cacheDir != "" { // Contradictory cache options
+		return nil, usageError(fs, errors.New("--cache-dir conflicts with --no-cache"))
+	}
+	if cfg.unique && !cfg.all { // Uniqueness filters --all's list
+		return nil, usageError(fs, errors.New("--unique requires --all"))
+	}
+	if cfg.size < 0 { // Negative size
+		return nil, usageError(fs, errors.New("--size must be positive"))
+	}
+	if cfg.all && cfg.size > 0 { // Fixed size has no list of solutions
+		return nil, usageError(fs, errors.New("--size cannot be combined with --all"))
+	}
+	if _, ok := searches[cfg.search]; !ok { // Unknown size search
+		return nil, usageError(fs, fmt.Errorf("unknown search order %q", cfg.search))
+	}
+	if cfg.anytime { // Shorthand for descending
+		if cfg.search != "ascending" && cfg.search != "descending" { // Contradicts the shorthand
+			return nil, usageError(fs, errors.New("--anytime conflicts with --search="+cfg.search))
+		}
+		cfg.search = "descending"
+	}
+	if cfg.search != "ascending" && (cfg.all || cfg.size > 0) { // Both fix the sizes to try
+		return nil, usageError(fs, errors.New("--search and --anytime cannot be combined with --all or --size"))
+	}
+	if cfg.searchLog != "" && (cfg.all || cfg.canonical || cfg.backend != "backtrack") { // Logs replay the default backtracker
+		return nil, usageError(fs, errors.New("--search-log records the default search only"))
+	}
+	if cfg.backend != "backtrack" && cfg.backend != "sat" { // Unknown backend
+		return nil, usageError(fs, fmt.Errorf("unknown backend %q", cfg.backend))
+	}
+	if (cfg.checkpoint != "" || cfg.resume != "") && // Checkpoints cover the default search only
+		(cfg.all || cfg.canonical || cfg.size > 0 || cfg.backend != "backtrack" || cfg.search != "ascending") {
+		return nil, usageError(fs, errors.New("--checkpoint and --resume support the default search only"))
+	}
+	if cfg.resume != "" && cfg.searchLog != "" { // A resumed log would lack its start
+		return nil, usageError(fs, errors.New("--search-log cannot record a resumed search"))
+	}
+	if cfg.checkpointEvery <= 0 { // Zero or negative interval
+		return nil, usageError(fs, errors.New("--checkpoint-every must be positive"))
+	}
+	if _, ok := orders[cfg.order]; !ok { // Unknown piece order
+		return nil, usageError(fs, fmt.Errorf("unknown piece order %q", cfg.order))
+	}
+	if cfg.order != "input" && (cfg.all || cfg.canonical || cfg.backend != "backtrack") { // Orders apply to the plain backtracker
+		return nil, usageError(fs, errors.New("--order applies to the default backtracker only"))
+	}
+	if cfg.order != "input" && (cfg.searchLog != "" || cfg.checkpoint != "" || cfg.resume != "") { // Replay and resume follow input order
+		return nil, usageError(fs, errors.New("--search-log, --checkpoint and --resume require --order=input"))
+	}
+	if cfg.backend != "backtrack" && (cfg.all || cfg.canonical) { // SAT neither enumerates nor compares boards
+		return nil, usageError(fs, errors.New("--backend cannot be combined with --all or --canonical"))
+	}
+
+	cfg.filename = positional[0] // The one positional argument
+	return cfg, nil
+}
 
-	return args[0], nil
+// usageError combines an argument error with the synopsis and flag list.
+func usageError(fs *flag.FlagSet, err error) error {
+	var b strings.Builder                            // Message, synopsis, then the flags
+	if err != nil && !errors.Is(err, flag.ErrHelp) { // Plain -h: synopsis only
+		fmt.Fprintln(&b, err)
+	}
+	b.WriteString(usage + "\n\nOptions:\n")
+	fs.SetOutput(&b) // PrintDefaults writes to the flag set's output
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)                                // Quiet again for later calls
+	return errors.New(strings.TrimSuffix(b.String(), "\n")) // Without the trailing newline
 }
@@ -2,18 +2,49 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
+// TestMain points the result cache at a temporary directory, so tests neither
+// read stale results nor fill the user's cache. The Go build cache used by
+// buildBinary is pinned first, since it defaults to the same location.
+func TestMain(m *testing.M) {
+	if os.Getenv("GOCACHE") == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			os.Setenv("GOCACHE", filepath.Join(dir, "go-build"))
+		}
+	}
+	dir, err := os.MkdirTemp("", "tetris-optimizer-cache-*")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("XDG_CACHE_HOME", dir) // os.UserCacheDir on Unix
+	os.Setenv("LocalAppData", dir)   // and on Windows
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		os.Setenv("HOME", dir)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestParseArgs(t *testing.T) {
 	tests := []struct {
 		name     string
 		args     []string
 		wantFile string
+		want     config
 		wantErr  bool
 	}{
 		{
@@ -32,11 +63,295 @@ func TestParseArgs(t *testing.T) {
 			args:    []string{"file1.txt", "file2.txt"},
 			wantErr: true,
 		},
+		{
+			name:     "all before file",
+			args:     []string{"--all", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{all: true},
+		},
+		{
+			name:     "flags after file",
+			args:     []string{"input.txt", "--all", "--unique"},
+			wantFile: "input.txt",
+			want:     config{all: true, unique: true},
+		},
+		{
+			name:    "unique without all",
+			args:    []string{"--unique", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "fixed size with search log",
+			args:     []string{"--size", "5", "--search-log", "out.log", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{size: 5, searchLog: "out.log"},
+		},
+		{
+			name:    "size with all",
+			args:    []string{"--all", "--size", "5", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "search log with canonical",
+			args:    []string{"--canonical", "--search-log", "out.log", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "sat backend",
+			args:     []string{"input.txt", "--backend=sat"},
+			wantFile: "input.txt",
+			want:     config{backend: "sat"},
+		},
+		{
+			name:    "unknown backend",
+			args:    []string{"--backend", "dlx", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "sat backend with canonical",
+			args:    []string{"--backend", "sat", "--canonical", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "anytime",
+			args:     []string{"--anytime", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{search: "descending"},
+		},
+		{
+			name:     "bisect",
+			args:     []string{"--search", "bisect", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{search: "bisect"},
+		},
+		{
+			name:    "anytime with bisect",
+			args:    []string{"--anytime", "--search", "bisect", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown search order",
+			args:    []string{"--search", "random", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "anytime with size",
+			args:    []string{"--anytime", "--size", "5", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "checkpoint with canonical",
+			args:    []string{"--checkpoint", "cp.json", "--canonical", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "resume with bisect",
+			args:    []string{"--resume", "cp.json", "--search", "bisect", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "resume with search log",
+			args:    []string{"--resume", "cp.json", "--search-log", "out.log", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "dynamic order",
+			args:     []string{"--order=dynamic", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{order: "dynamic"},
+		},
+		{
+			name:    "unknown order",
+			args:    []string{"--order=random", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "order with search log",
+			args:    []string{"--order=bbox", "--search-log", "out.log", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "restarts with seed",
+			args:     []string{"--restarts", "input.txt", "--seed", "7"},
+			wantFile: "input.txt",
+			want:     config{restarts: true, seed: 7, seedSet: true},
+		},
+		{
+			name:     "portfolio",
+			args:     []string{"--portfolio", "--stats", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{portfolio: true, stats: true},
+		},
+		{
+			name:    "portfolio with order",
+			args:    []string{"--portfolio", "--order=bbox", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "portfolio with checkpoint",
+			args:    []string{"--portfolio", "--checkpoint", "cp.json", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "timings",
+			args:     []string{"--timings", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{timings: "table"},
+		},
+		{
+			name:     "timings json",
+			args:     []string{"input.txt", "--timings=json"},
+			wantFile: "input.txt",
+			want:     config{timings: "json"},
+		},
+		{
+			name:    "timings unknown format",
+			args:    []string{"--timings=xml", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "progress json to a descriptor",
+			args:     []string{"--progress=json", "--progress-fd", "3", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{progress: "json", progressFD: 3},
+		},
+		{
+			name:    "unknown progress mode",
+			args:    []string{"--progress=xml", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "progress fd without json",
+			args:    []string{"--progress-fd", "3", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "progress to stdout",
+			args:    []string{"--progress=json", "--progress-fd", "1", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "live with progress none",
+			args:    []string{"--live", "--progress=none", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "profiles",
+			args:     []string{"--cpuprofile", "cpu.out", "input.txt", "--memprofile=mem.out", "--trace", "trace.out"},
+			wantFile: "input.txt",
+			want:     config{cpuProfile: "cpu.out", memProfile: "mem.out", traceFile: "trace.out"},
+		},
+		{
+			name:     "live",
+			args:     []string{"input.txt", "--live"},
+			wantFile: "input.txt",
+			want:     config{live: true},
+		},
+		{
+			name:    "live with all",
+			args:    []string{"--live", "--all", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "live with portfolio",
+			args:    []string{"--live", "--portfolio", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "color always",
+			args:     []string{"--color=always", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{color: "always"},
+		},
+		{
+			name:    "unknown color mode",
+			args:    []string{"--color=sometimes", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "box format",
+			args:     []string{"input.txt", "--format", "box"},
+			wantFile: "input.txt",
+			want:     config{format: "box"},
+		},
+		{
+			name:    "unknown format",
+			args:    []string{"--format=html", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "color with box format",
+			args:    []string{"--format=box", "--color=always", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "svg with grid",
+			args:     []string{"--format=svg", "--grid", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{format: "svg", image: imageFlags{grid: true, labels: true, cellSize: 40, border: 2, palette: "soft"}},
+		},
+		{
+			name:     "png options",
+			args:     []string{"--format=png", "--cell-size=12", "--border=0", "--palette=gray", "input.txt"},
+			wantFile: "input.txt",
+			want:     config{format: "png", image: imageFlags{labels: true, cellSize: 12, border: 0, palette: "gray"}},
+		},
+		{
+			name:    "border too wide",
+			args:    []string{"--format=png", "--cell-size=8", "--border=5", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown palette",
+			args:    []string{"--format=svg", "--palette=neon", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "cell size with box format",
+			args:    []string{"--format=box", "--cell-size=10", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "grid with text format",
+			args:    []string{"--grid", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "svg with all",
+			args:    []string{"--all", "--format=svg", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:     "cache dir",
+			args:     []string{"input.txt", "--cache-dir", "/tmp/c"},
+			wantFile: "input.txt",
+			want:     config{cacheDir: "/tmp/c"},
+		},
+		{
+			name:    "cache dir with no-cache",
+			args:    []string{"--no-cache", "--cache-dir", "/tmp/c", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "seed without restarts",
+			args:    []string{"--seed", "7", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "restarts with order",
+			args:    []string{"--restarts", "--order=bbox", "input.txt"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown flag",
+			args:    []string{"--bogus", "input.txt"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			file, err := parseArgs(tt.args)
+			cfg, err := parseArgs(tt.args)
 
 			if tt.wantErr {
 				if err == nil {
@@ -50,8 +365,55 @@ func TestParseArgs(t *testing.T) {
 				return
 			}
 
-			if file != tt.wantFile {
-				t.Errorf("parseArgs() file = %s, want %s", file, tt.wantFile)
+			if cfg.filename != tt.wantFile {
+				t.Errorf("parseArgs() file = %s, want %s", cfg.filename, tt.wantFile)
+			}
+			if cfg.all != tt.want.all || cfg.unique != tt.want.unique {
+				t.Errorf("parseArgs() all/unique = %v/%v, want %v/%v", cfg.all, cfg.unique, tt.want.all, tt.want.unique)
+			}
+			if cfg.size != tt.want.size || cfg.searchLog != tt.want.searchLog {
+				t.Errorf("parseArgs() size/searchLog = %d/%q, want %d/%q", cfg.size, cfg.searchLog, tt.want.size, tt.want.searchLog)
+			}
+			if want := tt.want.search; want != "" && cfg.search != want { // Empty: default not under test
+				t.Errorf("parseArgs() search = %q, want %q", cfg.search, want)
+			}
+			if want := tt.want.order; want != "" && cfg.order != want { // Empty: default not under test
+				t.Errorf("parseArgs() order = %q, want %q", cfg.order, want)
+			}
+			if cfg.restarts != tt.want.restarts || cfg.seed != tt.want.seed || cfg.seedSet != tt.want.seedSet {
+				t.Errorf("parseArgs() restarts/seed/seedSet = %v/%d/%v, want %v/%d/%v",
+					cfg.restarts, cfg.seed, cfg.seedSet, tt.want.restarts, tt.want.seed, tt.want.seedSet)
+			}
+			if cfg.noCache != tt.want.noCache || cfg.cacheDir != tt.want.cacheDir {
+				t.Errorf("parseArgs() noCache/cacheDir = %v/%q, want %v/%q", cfg.noCache, cfg.cacheDir, tt.want.noCache, tt.want.cacheDir)
+			}
+			if want := tt.want.color; want != "" && cfg.color != want { // Empty: default not under test
+				t.Errorf("parseArgs() color = %q, want %q", cfg.color, want)
+			}
+			if want := tt.want.format; want != "" && cfg.format != want { // Empty: default not under test
+				t.Errorf("parseArgs() format = %q, want %q", cfg.format, want)
+			}
+			if (cfg.format == "svg" || cfg.format == "png") && cfg.image != tt.want.image {
+				t.Errorf("parseArgs() image = %+v, want %+v", cfg.image, tt.want.image)
+			}
+			if cfg.timings != tt.want.timings {
+				t.Errorf("parseArgs() timings = %q, want %q", cfg.timings, tt.want.timings)
+			}
+			if want := tt.want.progress; want != "" && (cfg.progress != want || cfg.progressFD != tt.want.progressFD) { // Empty: default not under test
+				t.Errorf("parseArgs() progress = %q to %d, want %q to %d", cfg.progress, cfg.progressFD, want, tt.want.progressFD)
+			}
+			if cfg.cpuProfile != tt.want.cpuProfile || cfg.memProfile != tt.want.memProfile || cfg.traceFile != tt.want.traceFile {
+				t.Errorf("parseArgs() profiles = %q/%q/%q, want %q/%q/%q",
+					cfg.cpuProfile, cfg.memProfile, cfg.traceFile, tt.want.cpuProfile, tt.want.memProfile, tt.want.traceFile)
+			}
+			if cfg.live != tt.want.live {
+				t.Errorf("parseArgs() live = %v, want %v", cfg.live, tt.want.live)
+			}
+			if cfg.portfolio != tt.want.portfolio {
+				t.Errorf("parseArgs() portfolio = %v, want %v", cfg.portfolio, tt.want.portfolio)
+			}
+			if want := tt.want.backend; want != "" && cfg.backend != want { // Empty: default not under test
+				t.Errorf("parseArgs() backend = %q, want %q", cfg.backend, want)
 			}
 		})
 	}
@@ -260,6 +622,669 @@ func TestIntegration_NonExistentFile(t *testing.T) {
 	}
 }
 
+func TestIntegration_Verify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	puzzle := createTempFile(t, "##..\n##..\n....\n....\n")
+	defer os.Remove(puzzle)
+
+	tests := []struct {
+		name       string
+		args       []string
+		solution   string
+		wantOutput string
+		wantCode   int
+	}{
+		{"valid", nil, "AA\nAA\n", "OK", 0},
+		{"wrong label", nil, "BB\nBB\n", "INVALID", 1},
+		{"malformed grid", nil, "AA\nA.\n", "INVALID", 1},
+		{"valid but not minimal", nil, "AA.\nAA.\n...\n", "OK", 0},
+		{"minimal check fails", []string{"--minimal"}, "AA.\nAA.\n...\n", "INVALID", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solution := createTempFile(t, tt.solution)
+			defer os.Remove(solution)
+
+			args := append([]string{"verify"}, tt.args...)
+			cmd := exec.Command(binary, append(args, puzzle, solution)...)
+			output, _ := cmd.Output()
+
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Output = %q, want to contain %q", string(output), tt.wantOutput)
+			}
+			if code := cmd.ProcessState.ExitCode(); code != tt.wantCode {
+				t.Errorf("Exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+
+	// A solution that cannot be read is not a wrong solution
+	for name, solution := range map[string]string{"directory": t.TempDir(), "missing": filepath.Join(t.TempDir(), "none.txt")} {
+		t.Run("unreadable "+name, func(t *testing.T) {
+			cmd := exec.Command(binary, "verify", puzzle, solution)
+			output, _ := cmd.Output()
+			if strings.TrimSpace(string(output)) != "ERROR" {
+				t.Errorf("Output = %q, want ERROR", output)
+			}
+			if code := cmd.ProcessState.ExitCode(); code != 2 {
+				t.Errorf("Exit code = %d, want 2", code)
+			}
+		})
+	}
+}
+
+// TestIntegration_FixedSize checks the certificate printed when a size is infeasible.
+func TestIntegration_FixedSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	// L and J pieces: 3x3 must be searched exhaustively
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+
+	logFile := input + ".log"
+	defer os.Remove(logFile)
+
+	cmd := exec.Command(binary, "--size", "3", "--search-log", logFile, input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if string(output) != "NO SOLUTION\n" {
+		t.Errorf("Output = %q, want %q", output, "NO SOLUTION\n")
+	}
+	if !strings.Contains(stderr.String(), "size 3: exhausted after") {
+		t.Errorf("Stderr = %q, want exhaustive search certificate", stderr.String())
+	}
+
+	output, err = exec.Command(binary, "check-log", input, logFile).Output()
+	if err != nil {
+		t.Fatalf("check-log failed: %v", err)
+	}
+	if !strings.Contains(string(output), "size 3: exhausted") {
+		t.Errorf("check-log output = %q, want confirmed exhaustion", output)
+	}
+
+	// Far beyond the slot board: refused before a grid is allocated
+	cmd = exec.Command(binary, "--size", "40000", input)
+	stderr.Reset()
+	cmd.Stderr = &stderr
+	output, err = cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		t.Fatalf("--size 40000 error = %v, want exit code 1", err)
+	}
+	if len(output) != 0 || !strings.Contains(stderr.String(), "size 40000 is larger than 8") {
+		t.Errorf("--size 40000 output = %q, stderr = %q, want the limit explained", output, stderr.String())
+	}
+}
+
+// TestIntegration_Cache solves a puzzle twice through the cache, then corrupts
+// the stored board and checks that it is rejected and replaced.
+func TestIntegration_Cache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+	dir := t.TempDir()
+
+	solve := func(args ...string) (string, string) {
+		t.Helper()
+		cmd := exec.Command(binary, append(args, "--stats", input)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("Command failed: %v", err)
+		}
+		return string(out), stderr.String()
+	}
+
+	want, errOut := solve("--cache-dir", dir)
+	if strings.Contains(errOut, "cached result") {
+		t.Fatalf("first run hit the cache: %q", errOut)
+	}
+	entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(entries) != 1 {
+		t.Fatalf("cache holds %d entries, want 1", len(entries))
+	}
+
+	out, errOut := solve("--cache-dir", dir)
+	if out != want || !strings.Contains(errOut, "cached result") || !strings.Contains(errOut, "size 4: solved") {
+		t.Errorf("second run = %q / %q, want the cached board and its stats", out, errOut)
+	}
+	if _, errOut := solve("--cache-dir", dir, "--canonical"); strings.Contains(errOut, "cached result") {
+		t.Errorf("--canonical hit the default entry: %q", errOut)
+	}
+	if _, errOut := solve("--no-cache"); strings.Contains(errOut, "cached result") {
+		t.Errorf("--no-cache hit the cache: %q", errOut)
+	}
+
+	data, err := os.ReadFile(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := strings.NewReplacer("A", "B", "B", "A").Replace(string(data)) // Swap the labels
+	if err := os.WriteFile(entries[0], []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, errOut = solve("--cache-dir", dir)
+	if out != want || !strings.Contains(errOut, "ignoring cached result") {
+		t.Errorf("corrupted entry: run = %q / %q, want a fresh solve", out, errOut)
+	}
+	if out, errOut = solve("--cache-dir", dir); out != want || !strings.Contains(errOut, "cached result") {
+		t.Errorf("entry was not replaced: run = %q / %q", out, errOut)
+	}
+}
+
+// TestIntegration_Color checks when boards are coloured. Output goes to a pipe,
+// so auto mode never colours here.
+func TestIntegration_Color(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+
+	tests := []struct {
+		name    string
+		args    []string
+		noColor bool
+		want    bool
+	}{
+		{"auto on a pipe", nil, false, false},
+		{"always", []string{"--color=always"}, false, true},
+		{"always beats NO_COLOR", []string{"--color=always"}, true, true},
+		{"never", []string{"--color=never"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(binary, append(tt.args, "--no-cache", input)...)
+			if tt.noColor {
+				cmd.Env = append(os.Environ(), "NO_COLOR=1")
+			}
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if got := strings.Contains(string(output), "\x1b["); got != tt.want {
+				t.Errorf("Output = %q, coloured = %v, want %v", output, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIntegration_Render draws a solver's output with the render subcommand.
+// TestIntegration_Timings checks both timings formats name the phases of a run.
+func TestIntegration_Timings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(binary, "--no-cache", "--timings", input)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	for _, want := range []string{"parse ", "bounds size 3 ", "search size 3 ", "search size 4 ", "render ", "other ", "total "} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("--timings stderr = %q, want a %q row", stderr.String(), want)
+		}
+	}
+
+	stderr.Reset()
+	cmd = exec.Command(binary, "--no-cache", "--timings=json", input)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	var got struct {
+		Phases []struct {
+			Name    string
+			Size    int
+			Seconds float64
+		}
+		TotalSeconds float64 `json:"total_seconds"`
+	}
+	if err := json.Unmarshal(stderr.Bytes(), &got); err != nil {
+		t.Fatalf("--timings=json stderr = %q: %v", stderr.String(), err)
+	}
+	var names []string
+	for _, p := range got.Phases {
+		names = append(names, fmt.Sprintf("%s/%d", p.Name, p.Size))
+	}
+	if want := "parse/0 bounds/3 search/3 bounds/4 search/4 render/0"; strings.Join(names, " ") != want || got.TotalSeconds <= 0 {
+		t.Errorf("--timings=json phases = %v, total %v; want %s", names, got.TotalSeconds, want)
+	}
+}
+
+// TestIntegration_ProgressJSON checks the event stream on stderr and on another descriptor.
+func TestIntegration_ProgressJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+	bad := createTempFile(t, "####\n")
+	defer os.Remove(bad)
+
+	// events returns the "event" field of each line.
+	events := func(t *testing.T, stream []byte) []string {
+		var kinds []string
+		for _, line := range strings.Split(strings.TrimSpace(string(stream)), "\n") {
+			var e struct{ Event, Outcome string }
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("event line %q: %v", line, err)
+			}
+			kinds = append(kinds, e.Event+e.Outcome)
+		}
+		return kinds
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(binary, "--no-cache", "--progress=json", input)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	want := "started size_started size_exhausted size_started solution_found finishedsolved"
+	if got := strings.Join(events(t, stderr.Bytes()), " "); got != want {
+		t.Errorf("--progress=json events = %s, want %s", got, want)
+	}
+
+	stderr.Reset()
+	cmd = exec.Command(binary, "--no-cache", "--progress=json", bad)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	lines := strings.SplitN(stderr.String(), "\n", 2) // The reason comes first
+	if got := strings.Join(events(t, []byte(lines[1])), " "); got != "finishederror" {
+		t.Errorf("--progress=json events for a bad file = %s, want finishederror", got)
+	}
+
+	if runtime.GOOS == "windows" { // ExtraFiles are not supported
+		return
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	cmd = exec.Command(binary, "--no-cache", "--progress=json", "--progress-fd=3", input)
+	cmd.ExtraFiles = []*os.File{w} // Descriptor 3 in the child
+	stderr.Reset()
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stream, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if got := strings.Join(events(t, stream), " "); got != want || stderr.Len() != 0 {
+		t.Errorf("--progress-fd=3 events = %s, stderr %q; want %s and nothing on stderr", got, stderr.String(), want)
+	}
+}
+
+// TestIntegration_Profiles checks the profile flags write files the Go tools read.
+func TestIntegration_Profiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+
+	dir := t.TempDir()
+	cpu, mem, trace := filepath.Join(dir, "cpu.out"), filepath.Join(dir, "mem.out"), filepath.Join(dir, "trace.out")
+	output, err := exec.Command(binary, "--no-cache", "--cpuprofile", cpu, "--memprofile", mem, "--trace", trace, input).Output()
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if !strings.Contains(string(output), "A") {
+		t.Errorf("Output = %q, want the board", output)
+	}
+
+	for _, f := range []struct{ path, magic string }{
+		{cpu, "\x1f\x8b"}, // pprof profiles are gzipped
+		{mem, "\x1f\x8b"},
+		{trace, "go 1."},
+	} {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), f.magic) {
+			t.Errorf("%s starts with %q, want %q", filepath.Base(f.path), data[:min(len(data), 8)], f.magic)
+		}
+	}
+
+	logFile := filepath.Join(dir, "search.log")
+	cmd := exec.Command(binary, "--no-cache", "--search-log", logFile, "--cpuprofile", filepath.Join(dir, "missing", "cpu.out"), input)
+	if err := cmd.Run(); err == nil {
+		t.Error("--cpuprofile in a missing directory succeeded, want an error")
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("search log left behind after the failed start (stat error %v)", err)
+	}
+}
+
+func TestIntegration_Render(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+	solution, err := exec.Command(binary, "--no-cache", input).Output()
+	if err != nil {
+		t.Fatalf("solve failed: %v", err)
+	}
+	solutionFile := createTempFile(t, string(solution))
+	defer os.Remove(solutionFile)
+	box, err := exec.Command(binary, "--no-cache", "--format=box", input).Output()
+	if err != nil {
+		t.Fatalf("solve --format=box failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		want     string // Exact output, or a required substring if contains is set
+		contains bool
+		wantCode int
+	}{
+		{"svg by default", []string{solutionFile}, "", "<svg xmlns=", true, 0},
+		{"box matches solver", []string{"--format=box", solutionFile}, "", string(box), false, 0},
+		{"png", []string{"--format=png", "--palette=classic", solutionFile}, "", "\x89PNG\r\n", true, 0},
+		{"text from stdin", []string{"--format=text", "-"}, string(solution), string(solution), false, 0},
+		{"not a board", []string{input}, "", "ERROR", true, 2},
+		{"grid with box", []string{"--format=box", "--grid", solutionFile}, "", "", false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(binary, append([]string{"render"}, tt.args...)...)
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			output, _ := cmd.Output()
+			if code := cmd.ProcessState.ExitCode(); code != tt.wantCode {
+				t.Errorf("Exit code = %d, want %d", code, tt.wantCode)
+			}
+			if tt.contains && !strings.Contains(string(output), tt.want) || !tt.contains && string(output) != tt.want {
+				t.Errorf("Output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+// TestIntegration_ImageStatus checks that with image formats, status lines stay
+// out of stdout and a run without a board fails.
+func TestIntegration_ImageStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+	bad := createTempFile(t, "####\n")
+	defer os.Remove(bad)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string // Required prefix of stdout; empty for none at all
+		wantStderr string
+		wantCode   int
+	}{
+		{"solved", []string{"--format=svg", input}, "<svg", "", 0},
+		{"invalid input", []string{"--format=svg", bad}, "", "ERROR\n", 1},
+		{"no solution", []string{"--format=svg", "--size", "3", input}, "", "NO SOLUTION\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(binary, append([]string{"--no-cache"}, tt.args...)...)
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			output, _ := cmd.Output()
+			if code := cmd.ProcessState.ExitCode(); code != tt.wantCode {
+				t.Errorf("Exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.HasPrefix(string(output), tt.wantStdout) || tt.wantStdout == "" && len(output) != 0 {
+				t.Errorf("Stdout = %q, want prefix %q", output, tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("Stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+// TestIntegration_Replay records a search log and plays it back as text and GIF.
+func TestIntegration_Replay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	input := createTempFile(t, "#...\n#...\n##..\n....\n\n.#..\n.#..\n##..\n....\n")
+	defer os.Remove(input)
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "search.log")
+	solution, err := exec.Command(binary, "--no-cache", "--search-log", logFile, input).Output()
+	if err != nil {
+		t.Fatalf("solve failed: %v", err)
+	}
+	badLog := filepath.Join(dir, "bad.log")
+	if err := os.WriteFile(badLog, []byte("size 4\nplace A 3 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gifFile := filepath.Join(dir, "replay.gif")
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string // Required substring of stdout
+		wantCode int
+	}{
+		{"terminal", []string{"--speed", "0", input, logFile}, "solved after", 0},
+		{"every other step", []string{"--speed=0", "--every=2", input, logFile}, "step 2: ", 0},
+		{"gif", []string{"--gif", gifFile, "--speed", "50", input, logFile}, "", 0},
+		{"illegal placement", []string{"--speed=0", input, badLog}, "INVALID", 1},
+		{"gif needs a speed", []string{"--gif", gifFile, "--speed=0", input, logFile}, "", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(binary, append([]string{"replay"}, tt.args...)...)
+			output, _ := cmd.Output()
+			if code := cmd.ProcessState.ExitCode(); code != tt.wantCode {
+				t.Errorf("Exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(string(output), tt.want) {
+				t.Errorf("Output = %q, want to contain %q", output, tt.want)
+			}
+		})
+	}
+
+	output, _ := exec.Command(binary, "replay", "--speed=0", input, logFile).Output()
+	if !strings.HasSuffix(string(output), string(solution)+"\n") {
+		t.Errorf("replay ends with %q, want the solution", output)
+	}
+	data, err := os.ReadFile(gifFile)
+	if err != nil || !bytes.HasPrefix(data, []byte("GIF89a")) {
+		t.Errorf("replay --gif wrote %d bytes (%v), want a GIF", len(data), err)
+	}
+}
+
+// TestIntegration_Checkpoint interrupts a long search twice, resuming in between.
+func TestIntegration_Checkpoint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signal not supported on Windows")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	// The spec's hard example: 12 pieces, one empty cell, takes minutes
+	pieces := []string{
+		"....\n.##.\n.##.\n....", ".#..\n.##.\n.#..\n....", "....\n..##\n.##.\n....",
+		"....\n.##.\n.##.\n....", "....\n..#.\n.##.\n.#..", ".###\n...#\n....\n....",
+		"##..\n.#..\n.#..\n....", "....\n.##.\n.##.\n....", "....\n..##\n.##.\n....",
+		"##..\n.#..\n.#..\n....", ".#..\n.##.\n..#.\n....", "....\n###.\n.#..\n....",
+	}
+	input := createTempFile(t, strings.Join(pieces, "\n\n")+"\n")
+	defer os.Remove(input)
+	checkpoint := input + ".checkpoint"
+	defer os.Remove(checkpoint)
+
+	// interrupt runs the solver for a moment, then stops it like Ctrl+C
+	interrupt := func(args ...string) (string, string) {
+		cmd := exec.Command(binary, args...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+		cmd.Process.Signal(os.Interrupt)
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("Command failed: %v\n%s", err, stderr.String())
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	out, errOut := interrupt("--stats", "--checkpoint", checkpoint, input)
+	if out != "INTERRUPTED\n" || !strings.Contains(errOut, "checkpoint saved to") {
+		t.Fatalf("first run = %q / %q, want INTERRUPTED and a saved checkpoint", out, errOut)
+	}
+	first := cancelledNodes(t, errOut)
+
+	out, errOut = interrupt("--stats", "--resume", checkpoint, "--checkpoint", checkpoint, input)
+	if out != "INTERRUPTED\n" {
+		t.Fatalf("resumed run = %q, want INTERRUPTED", out)
+	}
+	if second := cancelledNodes(t, errOut); second <= first {
+		t.Errorf("resumed run stopped after %d nodes, want more than the first run's %d", second, first)
+	}
+
+	other := createTempFile(t, pieces[0]+"\n")
+	defer os.Remove(other)
+	if err := exec.Command(binary, "--resume", checkpoint, other).Run(); err == nil {
+		t.Error("resuming with a different puzzle succeeded, want error")
+	}
+}
+
+// cancelledNodes extracts N from a "size S: cancelled after N nodes" stats line.
+func cancelledNodes(t *testing.T, stats string) int64 {
+	t.Helper()
+	for _, line := range strings.Split(stats, "\n") {
+		var size int
+		var nodes int64
+		if _, err := fmt.Sscanf(line, "size %d: cancelled after %d nodes", &size, &nodes); err == nil {
+			return nodes
+		}
+	}
+	t.Fatalf("no cancelled size in stats %q", stats)
+	return 0
+}
+
+// TestIntegration_SAT round-trips a single O piece through cnf and from-sat.
+func TestIntegration_SAT(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration tests in short mode")
+	}
+
+	binary := buildBinary(t)
+	defer os.Remove(binary)
+
+	puzzle := createTempFile(t, "##..\n##..\n....\n....\n")
+	defer os.Remove(puzzle)
+
+	output, err := exec.Command(binary, "cnf", puzzle).Output()
+	if err != nil {
+		t.Fatalf("cnf failed: %v", err)
+	}
+	if !strings.Contains(string(output), "p cnf 1 1\n1 0\n") { // One placement, one at-least-one clause
+		t.Errorf("cnf output = %q, want single-variable formula", output)
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		wantOutput string
+	}{
+		{"satisfied", "s SATISFIABLE\nv 1 0\n", "AA\nAA\n"},
+		{"unsatisfiable", "s UNSATISFIABLE\n", "NO SOLUTION\n"},
+		{"wrong model", "s SATISFIABLE\nv -1 0\n", "INVALID\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := createTempFile(t, tt.model)
+			defer os.Remove(model)
+
+			output, _ := exec.Command(binary, "from-sat", puzzle, model).Output()
+			if string(output) != tt.wantOutput {
+				t.Errorf("from-sat output = %q, want %q", output, tt.wantOutput)
+			}
+		})
+	}
+}
+
 // buildBinary compiles the program to a temp file for integration tests.
 func buildBinary(t *testing.T) string {
 	t.Helper()
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profiler writes the profiles asked for by --cpuprofile, --memprofile and
+// --trace around the solve.
+type profiler struct {
+	cpu, mem, trace *os.File // nil when not asked for
+}
+
+// startProfiles creates the profile files, so a bad path fails before any
+// solving, and starts CPU profiling and the execution trace.
+func startProfiles(cfg *config) (*profiler, error) {
+	p := &profiler{} // Files stay nil unless asked for
+	var err error
+	for _, f := range []struct { // Create every requested file up front
+		path string
+		file **os.File
+	}{{cfg.cpuProfile, &p.cpu}, {cfg.memProfile, &p.mem}, {cfg.traceFile, &p.trace}} {
+		if f.path == "" { // Profile not asked for
+			continue
+		}
+		if *f.file, err = os.Create(f.path); err != nil { // Bad path: fail before solving
+			p.close()
+			return nil, err
+		}
+	}
+
+	if p.cpu != nil { // CPU profile samples from now on
+		if err := pprof.StartCPUProfile(p.cpu); err != nil {
+			p.close()
+			return nil, err
+		}
+	}
+	if p.trace != nil { // Execution trace from now on
+		if err := trace.Start(p.trace); err != nil {
+			if p.cpu != nil { // Undo the CPU profile already started
+				pprof.StopCPUProfile()
+			}
+			p.close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// stop ends CPU profiling and tracing, writes the heap profile and closes the
+// files. Returns the first error.
+func (p *profiler) stop() error {
+	if p.cpu != nil { // Flush the CPU samples
+		pprof.StopCPUProfile()
+	}
+	if p.trace != nil { // Flush the trace
+		trace.Stop()
+	}
+	var err error
+	if p.mem != nil { // Heap profile is written once, at the end
+		runtime.GC()                        // Up-to-date statistics of what the solve left allocated
+		err = pprof.WriteHeapProfile(p.mem) // Snapshot of live allocations
+	}
+	return errors.Join(err, p.close()) // Report both the profile and the close errors
+}
+
+// close closes the open profile files.
+func (p *profiler) close() error {
+	var errs []error                                      // One per file; nil entries vanish in Join
+	for _, f := range []*os.File{p.cpu, p.mem, p.trace} { // Close whichever files were opened
+		if f != nil { // Not asked for
+			errs = append(errs, f.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
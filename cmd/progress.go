@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// progressOutput returns where --progress=json events go: stderr for
+// descriptor 2, otherwise the descriptor fd, which the caller opened for
+// writing (e.g. "3>events.ndjson" in a shell).
+func progressOutput(fd int) (io.Writer, error) {
+	if fd == 2 { // Stderr needs no descriptor of its own
+		return os.Stderr, nil
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("descriptor %d", fd))
+	if f == nil { // Out of range for a descriptor
+		return nil, fmt.Errorf("--progress-fd %d: not a file descriptor", fd)
+	}
+	if _, err := f.Stat(); err != nil { // Not open
+		return nil, fmt.Errorf("--progress-fd %d: %v", fd, err)
+	}
+	return f, nil
+}
+
+// sizeHooks makes opts report the start and end of each board size to events.
+func sizeHooks(opts *internal.Options, events *internal.EventWriter) {
+	opts.OnSizeStart = func(size int) { // Announce the size before its bound check
+		events.Write(internal.Event{Event: "size_started", Size: size})
+	}
+	opts.OnSizeDone = func(r internal.SizeReport) { // Ruled out, exhausted, solved or cancelled
+		events.Write(internal.SizeEvent(r))
+	}
+}
+
+// finishedEvent returns the event that ends a run's --progress=json stream.
+func finishedEvent(result *internal.Result, cached, interrupted bool) internal.Event {
+	e := internal.Event{Event: "finished", Cached: cached}
+	if result.Board != nil { // Possibly not proven minimal, after a timeout
+		e.Size = result.Board.Size
+	}
+	for _, r := range result.Sizes { // Nodes of every size tried
+		e.TotalNodes += r.Nodes
+	}
+	switch { // Ctrl+C beats everything else
+	case interrupted:
+		e.Outcome = "interrupted"
+	case result.Err != nil: // A size broke down; see the error
+		e.Outcome, e.Error = "error", result.Err.Error()
+	case result.Timeout: // Stopped before a proof
+		e.Outcome = "timeout"
+	case result.Board == nil: // Size proven infeasible
+		e.Outcome = "no_solution"
+	default: // Board found and proven minimal
+		e.Outcome = "solved"
+	}
+	return e
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// renderUsage is the synopsis of the render subcommand.
+const renderUsage = "Usage: tetris-optimizer render [--format FORMAT] [image options] [--color MODE] <solution-file>"
+
+// formats lists the values of --format.
+var formats = map[string]bool{"text": true, "box": true, "svg": true, "png": true}
+
+// imageFlags holds the options of the image formats, svg and png.
+type imageFlags struct {
+	labels   bool   // Label pieces
+	grid     bool   // Draw all cell lines
+	cellSize int    // Pixels per cell
+	border   int    // Outline width in pixels; 0 for none
+	palette  string // Key of internal.Palettes
+}
+
+// register defines the image flags on fs.
+func (f *imageFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.labels, "labels", true, "with svg and png formats, write each piece's letter")
+	fs.BoolVar(&f.grid, "grid", false, "with svg and png formats, draw lines between all cells")
+	fs.IntVar(&f.cellSize, "cell-size", internal.DefaultCellSize, "with svg and png formats, cell side in `pixels`")
+	fs.IntVar(&f.border, "border", internal.DefaultBorder, "with svg and png formats, outline width in `pixels` (0: none)")
+	fs.StringVar(&f.palette, "palette", "soft", "with svg and png formats, colour `scheme`: "+strings.Join(internal.PaletteNames(), ", "))
+}
+
+// check validates the image flags and rejects them with a format that ignores them.
+func (f *imageFlags) check(fs *flag.FlagSet, format string) error {
+	var err error
+	fs.Visit(func(fl *flag.Flag) { // Only flags set on the command line
+		switch fl.Name {
+		case "labels", "grid", "cell-size", "border", "palette": // Image flags
+			if format != "svg" && format != "png" { // Text and box formats ignore them
+				err = fmt.Errorf("--%s applies to --format=svg and png only", fl.Name)
+			}
+		}
+	})
+	switch { // First problem wins
+	case err != nil:
+		return err
+	case f.cellSize < 4 || f.cellSize > 1000: // Too small to draw, or too large to be sensible
+		return errors.New("--cell-size must be between 4 and 1000")
+	case f.border < 0 || f.border > f.cellSize/2: // Outline must leave room for the fill
+		return errors.New("--border must be between 0 and half the cell size")
+	}
+	if _, ok := internal.Palettes[f.palette]; !ok { // Unknown palette name
+		return fmt.Errorf("unknown palette %q", f.palette)
+	}
+	return nil
+}
+
+// options converts the flags to image options.
+func (f *imageFlags) options() internal.ImageOptions {
+	pal := internal.Palettes[f.palette] // Copy: the options keep a pointer
+	border := f.border
+	if border == 0 { // ImageOptions reads zero as the default
+		border = -1
+	}
+	return internal.ImageOptions{CellSize: f.cellSize, Border: border, Palette: &pal, Labels: f.labels, Grid: f.grid}
+}
+
+// renderer returns the function drawing boards in the given format. The colour
+// mode applies to text only, the image flags to svg and png only.
+func renderer(format, color string, img imageFlags) func(*internal.Board) string {
+	switch format {
+	case "box": // Outlined text
+		return (*internal.Board).BoxString
+	case "svg": // Scalable image
+		opts := img.options()
+		return func(b *internal.Board) string { return b.SVG(opts) }
+	case "png": // Binary image
+		opts := img.options()
+		return func(b *internal.Board) string {
+			var buf bytes.Buffer
+			b.WritePNG(&buf, opts) // Cannot fail on a bytes.Buffer
+			return buf.String()
+		}
+	}
+	if useColor(color) { // Plain text, coloured on request
+		return (*internal.Board).ColorString
+	}
+	return (*internal.Board).String
+}
+
+// imageFormat reports whether format draws an image rather than text, so that
+// nothing else may be written to stdout.
+func imageFormat(format string) bool {
+	return format == "svg" || format == "png"
+}
+
+// checkBinaryOutput refuses to write PNG data to a terminal.
+func checkBinaryOutput(format string) error {
+	if format == "png" && internal.IsTerminal(os.Stdout) { // Binary garbage would wreck the terminal
+		return errors.New("refusing to write PNG to a terminal; redirect stdout to a file")
+	}
+	return nil
+}
+
+// useColor reports whether boards on stdout should be coloured. "auto" follows
+// the NO_COLOR convention (https://no-color.org) and colours only terminals;
+// an explicit "always" wins over NO_COLOR.
+func useColor(mode string) bool {
+	switch mode {
+	case "always": // Colour even into files and pipes
+		return true
+	case "never": // Plain letters everywhere
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && internal.IsTerminal(os.Stdout)
+}
+
+// runRender draws a solution file, or standard input if the name is "-", in
+// any output format; SVG by default. Prints "ERROR" and returns 2 if the grid
+// cannot be read.
+func runRender(args []string) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Errors are reported below
+	format := fs.String("format", "svg", "output format: text, box, svg or png")
+	color := fs.String("color", "auto", "with --format=text: auto, always or never")
+	var img imageFlags
+	img.register(fs)
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || !formats[*format] || // Bad flags, no single file, or unknown values
+		(*color != "auto" && *color != "always" && *color != "never") {
+		fmt.Fprintln(os.Stderr, renderUsage)
+		return 2
+	}
+	if err := img.check(fs, *format); err != nil { // Image flags need an image format
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, renderUsage)
+		return 2
+	}
+	if err := checkBinaryOutput(*format); err != nil { // PNG to a terminal
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var board *internal.Board
+	var err error
+	if fs.Arg(0) == "-" { // Straight from the solver: tetris-optimizer puzzle.txt | tetris-optimizer render -
+		board, err = internal.ParseBoard(os.Stdin)
+	} else { // Named file
+		board, err = readBoard(fs.Arg(0))
+	}
+	if err != nil { // Unreadable file or invalid grid
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fmt.Print(renderer(*format, *color, img)(board)) // Output in the chosen format
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// replayUsage is the synopsis of the replay subcommand.
+const replayUsage = "Usage: tetris-optimizer replay [--speed N] [--every N] [--gif FILE] [--cell-size N] [--palette NAME] [--color MODE] <puzzle-file> <search-log>"
+
+// runReplay plays a search log written with --search-log step by step, in the
+// terminal or into an animated GIF. Returns 0 when done, 1 if the log does not
+// fit the puzzle (printing "INVALID"), 2 on usage or file errors.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Errors are reported below
+	speed := fs.Float64("speed", 10, "steps per second (0: as fast as possible, terminal only)")
+	every := fs.Int("every", 1, "show only every Nth placement or undo")
+	gifFile := fs.String("gif", "", "write an animated GIF to `file` instead of playing in the terminal")
+	cellSize := fs.Int("cell-size", 16, "with --gif, cell side in pixels")
+	palette := fs.String("palette", "soft", "with --gif, colour scheme")
+	color := fs.String("color", "auto", "in the terminal: auto, always or never")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 || *speed < 0 || *every < 1 || // Bad flags, wrong arguments or values out of range
+		(*gifFile != "" && *speed == 0) || *cellSize < 4 || *cellSize > 1000 ||
+		(*color != "auto" && *color != "always" && *color != "never") {
+		fmt.Fprintln(os.Stderr, replayUsage)
+		return 2
+	}
+	if _, ok := internal.Palettes[*palette]; !ok { // Unknown palette name
+		fmt.Fprintf(os.Stderr, "unknown palette %q\n", *palette)
+		return 2
+	}
+
+	pieces, err := internal.ParseFile(fs.Arg(0)) // Same validation as solving
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logFile, err := os.Open(fs.Arg(1)) // Open the log; Replay streams it
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer logFile.Close() // Ensure file is closed on exit
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM) // Ctrl+C ends playback
+	defer stop()                                                                           // Restore default signal handling on exit
+
+	step := 0                                    // Events seen so far
+	shown := func(ev internal.TraceEvent) bool { // Size lines and outcomes are always shown
+		step++
+		return (ev.Kind != "place" && ev.Kind != "undo") || step%*every == 0
+	}
+
+	if *gifFile != "" { // Record instead of playing
+		return replayGIF(logFile, pieces, shown, *gifFile, *cellSize, *palette, *speed)
+	}
+
+	tty := internal.IsTerminal(os.Stdout)            // Redraw in place only on a terminal
+	render := renderer("text", *color, imageFlags{}) // Letters, coloured on request
+	err = internal.Replay(logFile, pieces, func(ev internal.TraceEvent, b *internal.Board) bool {
+		if !shown(ev) { // Skipped by --every
+			return true
+		}
+		if tty {
+			fmt.Print("\x1b[H\x1b[2J") // Draw each frame in place
+		}
+		fmt.Printf("step %d: %s\n%s", step, ev, render(b))
+		if !tty { // Piped: frames follow each other
+			fmt.Println() // Blank line between frames
+		}
+		if *speed > 0 { // Paced playback
+			select {
+			case <-time.After(time.Duration(float64(time.Second) / *speed)): // Next frame due
+			case <-ctx.Done(): // Interrupted while waiting
+			}
+		}
+		return ctx.Err() == nil // Stop on Ctrl+C
+	})
+	if err != nil { // Log does not fit the puzzle
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// replayGIF records the shown steps of a search log into an animated GIF.
+func replayGIF(log io.Reader, pieces []*internal.Tetromino, shown func(internal.TraceEvent) bool,
+	filename string, cellSize int, palette string, speed float64) int {
+	pal := internal.Palettes[palette]           // Copy: the options keep a pointer
+	delay := max(2, int(math.Round(100/speed))) // Hundredths of a second; viewers ignore shorter delays
+	rec := internal.NewGIFRecorder(internal.ImageOptions{CellSize: cellSize, Palette: &pal, Labels: true}, delay)
+
+	var addErr error // First frame that could not be added
+	err := internal.Replay(log, pieces, func(ev internal.TraceEvent, b *internal.Board) bool {
+		if shown(ev) { // Skipped steps are not recorded
+			addErr = rec.Add(b)
+		}
+		return addErr == nil // Stop at the frame cap
+	})
+	if err != nil { // Log does not fit the puzzle
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if addErr != nil { // Too many frames
+		fmt.Fprintf(os.Stderr, "%v; show fewer steps with --every\n", addErr)
+		return 2
+	}
+
+	f, err := os.Create(filename) // Written only once every frame is in
+	if err == nil {
+		err = rec.Encode(f)
+		if closeErr := f.Close(); err == nil { // Close errors lose data too
+			err = closeErr
+		}
+	}
+	if err != nil { // Create, encode or close failed
+		fmt.Fprintf(os.Stderr, "writing GIF: %v\n", err)
+		return 2
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d frames to %s\n", rec.Frames(), filename) // Summary on stderr; stdout stays empty
+	return 0
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// cnfUsage and fromSATUsage are the synopses of the SAT subcommands.
+const (
+	cnfUsage     = "Usage: tetris-optimizer cnf [--size N] <puzzle-file>"
+	fromSATUsage = "Usage: tetris-optimizer from-sat [--size N] <puzzle-file> <solver-output>"
+)
+
+// runCNF writes the DIMACS encoding of a puzzle to stdout.
+// Without --size it encodes the smallest size not ruled out by the cheap bounds.
+func runCNF(args []string) int {
+	fs := flag.NewFlagSet("cnf", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Errors are reported below
+	size := fs.Int("size", 0, "board size to encode")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || *size < 0 { // Bad flags, no single file, or a negative size
+		fmt.Fprintln(os.Stderr, cnfUsage)
+		return 2
+	}
+
+	pieces, err := internal.ParseFile(fs.Arg(0)) // Same validation as solving
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *size == 0 { // No --size: first size the bounds allow
+		*size = internal.LowerBound(pieces)
+	}
+
+	if err := internal.EncodeCNF(pieces, *size).WriteDIMACS(os.Stdout); err != nil { // Formula straight to stdout
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runFromSAT decodes a SAT solver's model for the puzzle's encoding and prints the board.
+// --size must match the size the formula was exported with.
+func runFromSAT(args []string) int {
+	fs := flag.NewFlagSet("from-sat", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Errors are reported below
+	size := fs.Int("size", 0, "board size the formula was encoded for")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 || *size < 0 { // Bad flags, wrong arguments, or a negative size
+		fmt.Fprintln(os.Stderr, fromSATUsage)
+		return 2
+	}
+
+	pieces, err := internal.ParseFile(fs.Arg(0)) // Same validation as solving
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *size == 0 {
+		*size = internal.LowerBound(pieces) // Same default as cnf
+	}
+
+	file, err := os.Open(fs.Arg(1)) // Solver output: a model or UNSAT
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer file.Close() // Ensure file is closed on exit
+
+	model, err := internal.ParseModel(file)
+	if errors.Is(err, internal.ErrUnsatisfiable) { // Solver proved the size infeasible
+		fmt.Println("NO SOLUTION")
+		return 0
+	}
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	b, err := internal.EncodeCNF(pieces, *size).Decode(model) // Decode checks the model with Verify
+	if err != nil {                                           // Model does not describe a valid board
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Print(b.String()) // Output solution grid to stdout
+	return 0
+}
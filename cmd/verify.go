@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/terry-xyz/tetris-optimizer/internal"
+)
+
+// verifyUsage is the synopsis of the verify subcommand.
+const verifyUsage = "Usage: tetris-optimizer verify [--minimal] <puzzle-file> <solution-file>"
+
+// runVerify checks a solution file against a puzzle file.
+// Prints "OK" and returns 0 when valid; prints "INVALID" (reason on stderr) and returns 1 otherwise.
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // Errors are reported below
+	minimal := fs.Bool("minimal", false, "also prove the board size is the smallest possible")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 { // Bad flags or wrong number of files
+		fmt.Fprintln(os.Stderr, verifyUsage)
+		return 2
+	}
+
+	pieces, err := internal.ParseFile(fs.Arg(0)) // Same validation as solving
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	board, err := readBoard(fs.Arg(1)) // Grid as printed by the solver
+	var perr *internal.ParseError
+	switch { // Classify the failure
+	case errors.As(err, &perr): // Malformed grid: the solution itself is wrong
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	case err != nil: // Could not read the file
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *minimal { // Search one size smaller
+		ctx, cancel := context.WithTimeout(context.Background(), internal.Timeout) // Same limit as solving
+		defer cancel()                                                             // Release the timer on exit
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)      // Ctrl+C aborts the proof
+		defer stop()                                                               // Restore default signal handling on exit
+		err = internal.VerifyMinimal(ctx, pieces, board)
+	} else { // Placement and coverage only
+		err = internal.Verify(pieces, board)
+	}
+
+	var verr *internal.VerifyError
+	switch { // Map the verdict to output and exit code
+	case err == nil: // Valid, and minimal if asked
+		fmt.Println("OK")
+		return 0
+	case errors.As(err, &verr): // Solution is wrong
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	default: // Minimality proof did not finish
+		fmt.Println("UNKNOWN")
+		fmt.Fprintf(os.Stderr, "could not prove minimality: %v\n", err)
+		return 2
+	}
+}
+
+// readBoard loads a solution grid as printed by the solver.
+func readBoard(filename string) (*internal.Board, error) {
+	file, err := os.Open(filename) // Open file for reading
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close() // Ensure file is closed on exit
+
+	return internal.ParseBoard(file) // Grid errors are ParseErrors, read errors not
+}
+
+// checkLogUsage is the synopsis of the check-log subcommand.
+const checkLogUsage = "Usage: tetris-optimizer check-log <puzzle-file> <search-log>"
+
+// runCheckLog replays a search log written with --search-log against its puzzle.
+// Prints the confirmed outcome of each size and returns 0, "INVALID" and 1 for
+// a log that does not match a search, or "ERROR" and 2 if it cannot be read.
+func runCheckLog(args []string) int {
+	if len(args) != 2 { // Exactly a puzzle and a log
+		fmt.Fprintln(os.Stderr, checkLogUsage)
+		return 2
+	}
+
+	pieces, err := internal.ParseFile(args[0]) // Same validation as solving
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	file, err := os.Open(args[1]) // Open the log; CheckLog streams it
+	if err != nil {
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer file.Close() // Ensure file is closed on exit
+
+	reports, err := internal.CheckLog(file, pieces) // Replay the log against the recomputed tree
+	var lerr *internal.LogError
+	if errors.As(err, &lerr) { // The log does not match a search
+		fmt.Println("INVALID")
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err != nil { // Could not read the log
+		fmt.Println("ERROR")
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	for _, r := range reports { // One confirmed outcome per size block
+		fmt.Printf("size %d: %s after %d nodes\n", r.Size, r.Outcome, r.Nodes)
+	}
+	return 0
+}
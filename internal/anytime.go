@@ -0,0 +1,108 @@
+// Package internal implements the bounded size searches, which always hold a solution.
+package internal
+
+import (
+	"context"
+)
+
+// solveDescending starts from the greedy board and tries ever smaller sizes, so
+// a valid (if not minimal) board is known from the first moment. Feasibility
+// only grows with size, so the first size that fails proves the best board so
+// far minimal. On cancellation the best board is returned with Timeout set.
+func solveDescending(ctx context.Context, pieces []*Tetromino, opts Options) *Result {
+	res := &Result{Board: Greedy(pieces).Trim()} // Valid from the start, if too large
+	floor := minSize(pieces)                     // Nothing smaller can hold every cell
+	searched := 0                                // Size whose search produced res.Board, 0 if none did
+
+	for size := res.Board.Size - 1; size >= floor; size-- { // Shrink until a size fails
+		if ctx.Err() != nil { // Check for cancellation before attempting
+			res.Timeout = true
+			return res
+		}
+
+		b, report := trySize(ctx, pieces, size, opts) // Bounds first, then the search
+		res.Sizes = append(res.Sizes, report)         // Record every size tried
+		switch report.Outcome {                       // What the size proved
+		case Solved: // Smaller board found
+			res.Board, searched = b.Trim(), size // Solution may leave whole rows and columns empty
+			size = res.Board.Size                // Skip sizes the trimmed board already beats (loop decrements)
+		case Cancelled: // Stopped: keep the best board so far
+			res.Timeout = true
+			return res
+		case Failed: // Search broke down; nothing proven
+			res.Err = report.Err
+			return res
+		default: // Ruled out or exhausted: no smaller size works either
+			return canonicalAt(ctx, pieces, opts, res, searched)
+		}
+	}
+	return canonicalAt(ctx, pieces, opts, res, searched)
+}
+
+// solveBisect binary-searches the sizes between LowerBound and the greedy
+// board. Like solveDescending it holds a valid board throughout, but it needs
+// only logarithmically many searches when the greedy bound is far off.
+func solveBisect(ctx context.Context, pieces []*Tetromino, opts Options) *Result {
+	res := &Result{Board: Greedy(pieces).Trim()} // Valid from the start, if too large
+	lo := LowerBound(pieces)                     // Every smaller size is ruled out
+	searched := 0                                // Size whose search produced res.Board, 0 if none did
+	if lo > 1 {                                  // Record the bound that rules out the size below
+		_, report := trySize(ctx, pieces, lo-1, opts)
+		res.Sizes = append(res.Sizes, report)
+	}
+
+	for lo < res.Board.Size { // Sizes lo..Board.Size-1 are undecided
+		if ctx.Err() != nil { // Check for cancellation before attempting
+			res.Timeout = true
+			return res
+		}
+
+		mid := (lo + res.Board.Size - 1) / 2         // Lower middle of the undecided range
+		b, report := trySize(ctx, pieces, mid, opts) // Bounds first, then the search
+		res.Sizes = append(res.Sizes, report)        // Record every size tried
+		switch report.Outcome {                      // What the size proved
+		case Solved: // Board.Size shrinks to mid or below
+			res.Board, searched = b.Trim(), mid
+		case Cancelled: // Stopped: keep the best board so far
+			res.Timeout = true
+			return res
+		case Failed: // Search broke down; nothing proven
+			res.Err = report.Err
+			return res
+		default: // Ruled out or exhausted: so is every smaller size
+			lo = mid + 1
+		}
+	}
+	return canonicalAt(ctx, pieces, opts, res, searched)
+}
+
+// canonicalAt makes a proven-minimal result honour Options.Canonical: a board
+// trimmed from a larger search, or built by Greedy, is not the canonical one,
+// so its size is searched once more. Other results are returned unchanged.
+func canonicalAt(ctx context.Context, pieces []*Tetromino, opts Options, res *Result, searched int) *Result {
+	if !opts.Canonical || searched == res.Board.Size { // Not asked for, or already canonical
+		return res
+	}
+	b, report := trySize(ctx, pieces, res.Board.Size, opts) // Search the proven size once more
+	res.Sizes = append(res.Sizes, report)
+	switch report.Outcome { // What the extra search found
+	case Solved: // The canonical board
+		res.Board = b
+	case Failed: // Search broke down; nothing proven
+		res.Err = report.Err
+	default: // Cancelled: keep the valid but non-canonical board
+		res.Timeout = true
+	}
+	return res
+}
+
+// slotBoard places each piece in its own 4x4 slot of a square grid of slots.
+// Wasteful, but valid for any input and built without searching.
+func slotBoard(pieces []*Tetromino) *Board {
+	b := NewBoard(maxSize(pieces)) // Every slot is 4x4
+	slots := b.Size / 4            // Slots per side
+	for i, p := range pieces {     // Piece i in slot row i/slots, column i%slots
+		b.Place(p, 4*(i/slots), 4*(i%slots)) // Shapes are normalized to their 4x4 bounding box
+	}
+	return b
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+// anytimePieces are small inputs whose minimum the bounded searches must reach.
+var anytimePieces = []struct {
+	name   string
+	pieces []*Tetromino
+}{
+	{"L and J", logPieces},
+	{"single O", []*Tetromino{{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}}}},
+	{"mixed", []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+		{Label: 'C', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}},
+		{Label: 'D', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		{Label: 'E', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}},
+}
+
+func TestSlotBoard(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+
+	b := slotBoard(pieces)
+	if b.Size != 16 { // 12 pieces need a 4x4 grid of slots
+		t.Errorf("slotBoard() size = %d, want 16", b.Size)
+	}
+	if err := Verify(pieces, b); err != nil {
+		t.Errorf("slotBoard() invalid: %v\n%s", err, b)
+	}
+}
+
+// TestSolveWith_BoundedSearch checks that the downward searches still end at the minimum.
+func TestSolveWith_BoundedSearch(t *testing.T) {
+	for _, search := range []SizeSearch{Descending, Bisect} {
+		for _, tt := range anytimePieces {
+			t.Run(search.String()+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				want := Solve(ctx, tt.pieces)
+
+				got := SolveWith(ctx, tt.pieces, Options{Search: search})
+				if got.Timeout || got.Board == nil {
+					t.Fatalf("SolveWith(%v) = %+v, want a proven board", search, got.Sizes)
+				}
+				if got.Board.Size != want.Board.Size {
+					t.Errorf("SolveWith(%v) size = %d, want %d", search, got.Board.Size, want.Board.Size)
+				}
+				if err := Verify(tt.pieces, got.Board); err != nil {
+					t.Errorf("SolveWith(%v) board invalid: %v\n%s", search, err, got.Board)
+				}
+
+				canonical := SolveWith(ctx, tt.pieces, Options{Search: search, Canonical: true})
+				wantCanonical := SolveWith(ctx, tt.pieces, Options{Canonical: true})
+				if canonical.Board.String() != wantCanonical.Board.String() {
+					t.Errorf("SolveWith(%v, Canonical) =\n%s\nwant\n%s", search, canonical.Board, wantCanonical.Board)
+				}
+			})
+		}
+	}
+}
+
+// TestSolveWith_BoundedSearchCancelled checks that a cancelled search still returns a valid board.
+func TestSolveWith_BoundedSearchCancelled(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Nothing can be searched
+
+	for _, search := range []SizeSearch{Descending, Bisect} {
+		result := SolveWith(ctx, pieces, Options{Search: search})
+		if !result.Timeout {
+			t.Errorf("SolveWith(%v, cancelled) Timeout = false, want true", search)
+		}
+		if result.Board == nil {
+			t.Fatalf("SolveWith(%v, cancelled) returned nil board, want best found", search)
+		}
+		if err := Verify(pieces, result.Board); err != nil {
+			t.Errorf("SolveWith(%v, cancelled) best board invalid: %v\n%s", search, err, result.Board)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 // Package internal provides board representation and operations for tetromino placement.
 package internal
 
+import "sort"
+
 // Board represents the game board as a 2D grid.
 type Board struct {
 	Grid [][]byte // 2D slice storing cell values ('.' for empty, 'A'-'Z' for pieces)
@@ -92,3 +94,83 @@ func (b *Board) CountEmpty() int {
 	}
 	return count
 }
+
+// Trim returns the smallest square board that still holds every piece, cut from
+// b without moving pieces relative to each other. Returns b itself if nothing
+// can be removed.
+func (b *Board) Trim() *Board {
+	top, left, bottom, right := b.Size, b.Size, -1, -1 // Occupied bounding box
+	for r, row := range b.Grid {                       // Iterate through each row
+		for c, cell := range row { // Iterate through each cell
+			if cell != '.' { // Occupied cell widens the box
+				top, bottom = min(top, r), max(bottom, r)
+				left, right = min(left, c), max(right, c)
+			}
+		}
+	}
+	if bottom < 0 { // Empty board
+		return NewBoard(0)
+	}
+
+	size := max(bottom-top+1, right-left+1) // Side of the smallest square around the box
+	if size == b.Size {                     // Nothing to trim
+		return b
+	}
+	top, left = min(top, b.Size-size), min(left, b.Size-size) // Square must stay on the board
+	t := NewBoard(size)                                       // Allocate the trimmed board
+	for r := range t.Grid {                                   // Copy the square row by row
+		copy(t.Grid[r], b.Grid[top+r][left:left+size])
+	}
+	return t
+}
+
+// Placement records where a piece sits on a board.
+type Placement struct {
+	Piece    *Tetromino // Label and normalized shape
+	Row, Col int        // Board position of the shape's origin (top-left of its bounding box)
+}
+
+// Placements reconstructs the placement of every labelled piece, ordered by label.
+// Calling Place with each result on an empty board of the same size reproduces b.
+// Labels are grouped by character only; callers needing well-formed tetrominoes
+// should check the shapes (ParseBoard does).
+func (b *Board) Placements() []Placement {
+	cells, order := b.labelCells()
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] }) // Label order, not board order
+
+	placements := make([]Placement, 0, len(order)) // One placement per label
+	for _, label := range order {                  // Pieces in label order
+		coords := Normalize(cells[label]) // Shape relative to its bounding box
+		origin := cells[label][0]         // Row-major scan: first cell has the minimum row
+		for _, p := range cells[label] {  // Minimum column can come from a later row
+			if p.Col < origin.Col { // Found a cell further left
+				origin.Col = p.Col
+			}
+		}
+		placements = append(placements, Placement{ // Label, shape and position
+			Piece: &Tetromino{Label: label, Coords: coords},
+			Row:   origin.Row,
+			Col:   origin.Col,
+		})
+	}
+	return placements
+}
+
+// labelCells groups non-empty cells by label.
+// Returns the cells per label in row-major order and the labels in order of first appearance.
+func (b *Board) labelCells() (map[byte][]Point, []byte) {
+	cells := make(map[byte][]Point) // Label -> cells covered by that label
+	var order []byte                // Labels in row-major order of first appearance
+	for r, row := range b.Grid {    // Iterate through each row
+		for c, cell := range row { // Iterate through each cell
+			if cell == '.' { // Empty cells belong to no piece
+				continue
+			}
+			if _, ok := cells[cell]; !ok { // First time this label is seen
+				order = append(order, cell)
+			}
+			cells[cell] = append(cells[cell], Point{Row: r, Col: c}) // Add cell to its label's list
+		}
+	}
+	return cells, order
+}
@@ -174,3 +174,49 @@ func TestBoardCountEmpty(t *testing.T) {
 		})
 	}
 }
+
+// TestBoardPlacements checks that placements replay onto an empty board to reproduce it.
+func TestBoardPlacements(t *testing.T) {
+	b := NewBoard(4)
+	b.Place(&Tetromino{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}}, 1, 2) // J up
+	b.Place(&Tetromino{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}}, 0, 0) // O
+
+	placements := b.Placements()
+	if len(placements) != 2 {
+		t.Fatalf("Placements() returned %d entries, want 2", len(placements))
+	}
+
+	wantOrigin := []Point{{0, 0}, {1, 2}} // Sorted by label: A then B
+	replay := NewBoard(b.Size)
+	for i, p := range placements {
+		if p.Row != wantOrigin[i].Row || p.Col != wantOrigin[i].Col {
+			t.Errorf("Placements()[%d] origin = (%d,%d), want (%d,%d)", i, p.Row, p.Col, wantOrigin[i].Row, wantOrigin[i].Col)
+		}
+		replay.Place(p.Piece, p.Row, p.Col)
+	}
+	if replay.String() != b.String() {
+		t.Errorf("replayed board = %q, want %q", replay.String(), b.String())
+	}
+}
+
+func TestBoard_Trim(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []string
+		want string
+	}{
+		{"already tight", []string{"AA", "AA"}, "AA\nAA\n"},
+		{"top-left corner", []string{"AA..", "AA..", "....", "...."}, "AA\nAA\n"},
+		{"offset piece", []string{"....", ".A..", ".AAA", "...."}, "A..\nAAA\n...\n"},
+		{"square kept on board", []string{"....", "....", "....", "AAAA"}, "....\n....\n....\nAAAA\n"},
+		{"empty", []string{"..", ".."}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boardFromRows(tt.rows...).Trim().String(); got != tt.want {
+				t.Errorf("Trim() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package internal proves board sizes infeasible with cheap counting arguments.
+package internal
+
+import "fmt"
+
+// Infeasibility explains why the pieces cannot fit on a board of a given size.
+type Infeasibility struct {
+	Size   int    // Board size that was ruled out
+	Bound  string // Name of the argument: "area", "piece-size", "lines" or a colouring
+	Reason string // Human-readable explanation
+}
+
+func (inf *Infeasibility) String() string {
+	return fmt.Sprintf("%s: %s", inf.Bound, inf.Reason)
+}
+
+// colouring assigns each cell to one of two colours (true/false).
+type colouring struct {
+	name  string
+	color func(row, col int) bool
+}
+
+// colourings are the two-colourings checked by CheckBounds. Each tetromino covers
+// a fixed imbalance between the colours wherever it is placed (up to sign), so the
+// pieces' imbalances must be able to cancel out the board's own imbalance.
+var colourings = []colouring{
+	{"checkerboard", func(r, c int) bool { return (r+c)%2 == 0 }},
+	{"column-stripes", func(r, c int) bool { return c%2 == 0 }},
+	{"row-stripes", func(r, c int) bool { return r%2 == 0 }},
+}
+
+// CheckBounds runs the cheap infeasibility arguments for one board size.
+// Returns nil if none of them rules the size out; the size may still be
+// infeasible, which only a search can tell.
+func CheckBounds(pieces []*Tetromino, size int) *Infeasibility {
+	if cells := 4 * len(pieces); cells > size*size { // Not enough room for every cell
+		return &Infeasibility{Size: size, Bound: "area",
+			Reason: fmt.Sprintf("%d cells do not fit in %d", cells, size*size)}
+	}
+
+	for _, p := range pieces { // Every piece must fit on its own
+		if h, w := extent(p.Coords); h > size || w > size { // Taller or wider than the board
+			return &Infeasibility{Size: size, Bound: "piece-size",
+				Reason: fmt.Sprintf("piece %c spans %dx%d", p.Label, h, w)}
+		}
+	}
+
+	if inf := checkLines(pieces, size); inf != nil { // Too many straight pieces for the lines
+		return inf
+	}
+
+	for _, col := range colourings { // Try each parity argument
+		if inf := checkColouring(pieces, size, col); inf != nil { // Imbalances cannot cancel out
+			return inf
+		}
+	}
+
+	return nil
+}
+
+// LowerBound returns the smallest board size that CheckBounds does not rule out.
+// Every solution is at least this large.
+func LowerBound(pieces []*Tetromino) int {
+	size := minSize(pieces)                // Area bound: nothing smaller holds the cells
+	for CheckBounds(pieces, size) != nil { // Terminates: large enough boards pass every bound
+		size++
+	}
+	return size
+}
+
+// checkLines bounds the number of straight I pieces: a vertical I needs 4
+// consecutive cells of one column, so each column holds at most size/4 of them
+// (likewise rows for horizontal ones).
+func checkLines(pieces []*Tetromino, size int) *Infeasibility {
+	vertical, horizontal := 0, 0 // Straight pieces by orientation
+	for _, p := range pieces {   // Count straight pieces
+		switch h, w := extent(p.Coords); { // Classify by bounding box
+		case h == 4: // Four tall: vertical I
+			vertical++
+		case w == 4: // Four wide: horizontal I
+			horizontal++
+		}
+	}
+
+	limit := size * (size / 4) // size lines, size/4 pieces each
+	if vertical > limit {      // More vertical I pieces than columns can hold
+		return &Infeasibility{Size: size, Bound: "lines",
+			Reason: fmt.Sprintf("%d vertical I pieces but %d columns hold at most %d", vertical, size, limit)}
+	}
+	if horizontal > limit { // More horizontal I pieces than rows can hold
+		return &Infeasibility{Size: size, Bound: "lines",
+			Reason: fmt.Sprintf("%d horizontal I pieces but %d rows hold at most %d", horizontal, size, limit)}
+	}
+	return nil
+}
+
+// checkColouring applies a two-colouring parity argument. A piece with imbalance
+// a (cells of one colour minus the other) contributes +a or -a depending on where
+// it sits; the total must equal the board's imbalance minus that of the empty
+// cells, which is at most the number of empty cells in absolute value.
+func checkColouring(pieces []*Tetromino, size int, col colouring) *Infeasibility {
+	boardImbalance := 0         // Colour-true cells minus colour-false cells on the board
+	for r := 0; r < size; r++ { // Iterate through each row
+		for c := 0; c < size; c++ { // Iterate through each column
+			boardImbalance += sign(col.color(r, c))
+		}
+	}
+	empty := size*size - 4*len(pieces) // Cells left uncovered by the pieces
+
+	sums := map[int]bool{0: true} // Reachable totals of ±imbalance over pieces so far
+	for _, p := range pieces {    // Extend the reachable totals piece by piece
+		a := 0                        // This piece's imbalance
+		for _, pt := range p.Coords { // Sum over the piece's cells
+			a += sign(col.color(pt.Row, pt.Col))
+		}
+		if a == 0 { // Balanced piece never changes the total
+			continue
+		}
+		next := make(map[int]bool, 2*len(sums)) // Totals including this piece
+		for s := range sums {                   // Either sign of the imbalance
+			next[s+a] = true
+			next[s-a] = true
+		}
+		sums = next
+	}
+
+	for s := range sums { // Any reachable total close enough wins
+		if abs(boardImbalance-s) <= empty { // Empty cells can absorb the difference
+			return nil
+		}
+	}
+	return &Infeasibility{Size: size, Bound: col.name,
+		Reason: fmt.Sprintf("piece colour imbalances cannot cancel the board's %+d with %d empty cells", boardImbalance, empty)}
+}
+
+// extent returns the height and width of a shape's bounding box.
+func extent(coords []Point) (int, int) {
+	n := Normalize(coords) // Shape at the origin
+	h, w := 0, 0           // Bounding box so far
+	for _, p := range n {  // Grow the box to cover every cell
+		h = max(h, p.Row+1)
+		w = max(w, p.Col+1)
+	}
+	return h, w
+}
+
+// sign maps a colour to +1 or -1.
+func sign(b bool) int {
+	if b { // Colour true
+		return 1
+	}
+	return -1
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 { // Negative
+		return -x
+	}
+	return x
+}
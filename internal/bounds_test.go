@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+var (
+	boundsO     = []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	boundsT     = []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}
+	boundsIVert = []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}
+	boundsL     = []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}
+	boundsJ     = []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}
+)
+
+func TestCheckBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		shapes    [][]Point
+		size      int
+		wantBound string // "" means no bound applies
+	}{
+		{"area", [][]Point{boundsO, boundsO}, 2, "area"},
+		{"I needs size 4", [][]Point{boundsIVert}, 3, "piece-size"},
+		{"I fits size 4", [][]Point{boundsIVert}, 4, ""},
+		{"six vertical I in 5x5", [][]Point{boundsIVert, boundsIVert, boundsIVert, boundsIVert, boundsIVert, boundsIVert}, 5, "lines"},
+		{"odd T count on full board", [][]Point{boundsT, boundsO, boundsO, boundsO}, 4, "checkerboard"},
+		{"even T count on full board", [][]Point{boundsT, boundsT, boundsO, boundsO}, 4, ""},
+		{"two O in 3x3", [][]Point{boundsO, boundsO}, 3, "column-stripes"},
+		{"L and J in 3x3", [][]Point{boundsL, boundsJ}, 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := make([]*Tetromino, len(tt.shapes))
+			for i, s := range tt.shapes {
+				pieces[i] = &Tetromino{Label: byte('A' + i), Coords: s}
+			}
+
+			inf := CheckBounds(pieces, tt.size)
+			switch {
+			case tt.wantBound == "" && inf != nil:
+				t.Errorf("CheckBounds() = %v, want nil", inf)
+			case tt.wantBound != "" && inf == nil:
+				t.Errorf("CheckBounds() = nil, want %s", tt.wantBound)
+			case inf != nil && (inf.Bound != tt.wantBound || inf.Size != tt.size):
+				t.Errorf("CheckBounds() = %v at size %d, want %s at size %d", inf, inf.Size, tt.wantBound, tt.size)
+			}
+		})
+	}
+}
+
+// TestCheckBounds_Sound ensures no bound ever rules out a size that has a solution.
+func TestCheckBounds_Sound(t *testing.T) {
+	sets := [][][]Point{
+		{boundsO},
+		{boundsT, boundsT, boundsO, boundsO},
+		{boundsIVert, boundsIVert, boundsIVert, boundsIVert},
+		{boundsL, boundsJ, boundsT},
+		{boundsT, boundsT, boundsT, boundsL, boundsJ},
+	}
+
+	for _, shapes := range sets {
+		pieces := make([]*Tetromino, len(shapes))
+		for i, s := range shapes {
+			pieces[i] = &Tetromino{Label: byte('A' + i), Coords: s}
+		}
+
+		result := Solve(context.Background(), pieces)
+		if result.Board == nil {
+			t.Fatal("Solve() returned nil board")
+		}
+		for size := result.Board.Size; size <= result.Board.Size+2; size++ {
+			if inf := CheckBounds(pieces, size); inf != nil {
+				t.Errorf("CheckBounds(size %d) = %v, but a solution exists:\n%s", size, inf, result.Board)
+			}
+		}
+	}
+}
+
+func TestLowerBound(t *testing.T) {
+	tests := []struct {
+		name   string
+		shapes [][]Point
+		want   int
+	}{
+		{"single O", [][]Point{boundsO}, 2},
+		{"two O", [][]Point{boundsO, boundsO}, 4}, // 3x3 ruled out by column stripes
+		{"single I", [][]Point{boundsIVert}, 4},   // I piece needs 4x4
+		{"L and J", [][]Point{boundsL, boundsJ}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := make([]*Tetromino, len(tt.shapes))
+			for i, s := range tt.shapes {
+				pieces[i] = &Tetromino{Label: byte('A' + i), Coords: s}
+			}
+			if got := LowerBound(pieces); got != tt.want {
+				t.Errorf("LowerBound() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package internal caches solved puzzles on disk.
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cache stores finished results on disk, one JSON file per key under Dir.
+// Only searches that proved their board minimal belong in it. Entries are
+// files anyone can edit, so Get verifies every board against the pieces
+// before returning it.
+type Cache struct {
+	Dir string
+}
+
+// cacheEntry is the stored form of a result.
+type cacheEntry struct {
+	Shapes   []int        `json:"shapes"`             // ShapeID of each input piece, to reject a different puzzle
+	Config   string       `json:"config"`             // Solver configuration that produced the board
+	Board    []string     `json:"board"`              // Rows of the solution
+	Sizes    []SizeReport `json:"sizes"`              // Per-size reports of the original run
+	Strategy string       `json:"strategy,omitempty"` // Winning strategy of a portfolio run
+}
+
+// DefaultCacheDir returns the per-user directory for cached results.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir() // Per-user cache directory
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tetris-optimizer"), nil // Subdirectory of our own
+}
+
+// CacheKey hashes the shape IDs of the pieces, in input order, together with
+// a description of the solver configuration (such as Options.String). Labels
+// follow input order, so two puzzles with the same key have the same answers.
+func CacheKey(pieces []*Tetromino, config string) string {
+	h := sha256.New()          // Hash of the shapes and configuration
+	for _, p := range pieces { // Shapes in input order
+		fmt.Fprintf(h, "%d,", ShapeID(p.Coords)) // Separator keeps IDs apart
+	}
+	fmt.Fprintf(h, "\n%s", config)        // Configuration after the shapes
+	return hex.EncodeToString(h.Sum(nil)) // Hex file name
+}
+
+// path returns the file holding the entry for key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json") // One file per key
+}
+
+// Get returns the cached result for key, or nil if there is none. An entry that
+// cannot be read or whose board is not a valid placement of the pieces is an
+// error; the caller should solve again and overwrite it.
+func (c *Cache) Get(key string, pieces []*Tetromino) (*Result, error) {
+	data, err := os.ReadFile(c.path(key)) // Whole entry at once
+	if errors.Is(err, fs.ErrNotExist) {   // Miss
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var e cacheEntry                                 // Stored form
+	if err := json.Unmarshal(data, &e); err != nil { // Not a cache entry
+		return nil, fmt.Errorf("cache entry %s: %w", key, err)
+	}
+	if len(e.Shapes) != len(pieces) { // Different number of pieces
+		return nil, fmt.Errorf("cache entry %s is for a different puzzle", key)
+	}
+	for i, p := range pieces { // Same shapes in the same order
+		if e.Shapes[i] != ShapeID(p.Coords) { // A piece differs
+			return nil, fmt.Errorf("cache entry %s is for a different puzzle", key)
+		}
+	}
+
+	b, err := ParseBoard(strings.NewReader(strings.Join(e.Board, "\n") + "\n")) // Stored rows back into a board
+	if err != nil {                                                             // Edited into an invalid grid
+		return nil, fmt.Errorf("cache entry %s: %w", key, err)
+	}
+	if err := Verify(pieces, b); err != nil { // Never trust a stored board unchecked
+		return nil, fmt.Errorf("cache entry %s: %w", key, err)
+	}
+	return &Result{Board: b, Sizes: e.Sizes, Strategy: e.Strategy}, nil // Verified board
+}
+
+// Put stores a finished result under key. The entry is written to a temporary
+// file and renamed into place, so concurrent readers never see half of it.
+func (c *Cache) Put(key, config string, pieces []*Tetromino, result *Result) error {
+	if result.Timeout || result.Board == nil { // Timed out or no board: nothing proven
+		return errors.New("only finished results can be cached")
+	}
+	e := cacheEntry{ // Stored form of the result
+		Shapes:   make([]int, len(pieces)),                                             // Filled in below
+		Config:   config,                                                               // Configuration, for inspection
+		Board:    strings.Split(strings.TrimSuffix(result.Board.String(), "\n"), "\n"), // Rows without the final newline
+		Sizes:    result.Sizes,                                                         // Per-size reports
+		Strategy: result.Strategy,                                                      // Portfolio winner, if any
+	}
+	for i, p := range pieces { // Fingerprint every piece
+		e.Shapes[i] = ShapeID(p.Coords) // Shape of piece i
+	}
+	data, err := json.MarshalIndent(e, "", "  ") // Indented for hand inspection
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil { // First use: create the directory
+		return err
+	}
+	f, err := os.CreateTemp(c.Dir, key+"-*.tmp") // Same directory, so the rename is atomic
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))   // Entry plus a trailing newline
+	if closeErr := f.Close(); err == nil { // Close errors lose data too
+		err = closeErr
+	}
+	if err == nil { // Written in full
+		err = os.Rename(f.Name(), c.path(key)) // Replace any old entry at once
+	}
+	if err != nil { // Write, close or rename failed
+		os.Remove(f.Name()) // Remove the partial file
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCache_RoundTrip checks that a stored result comes back intact.
+func TestCache_RoundTrip(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:6]
+	cache := &Cache{Dir: filepath.Join(t.TempDir(), "cache")} // Created on first Put
+	key := CacheKey(pieces, Options{}.String())
+
+	if got, err := cache.Get(key, pieces); got != nil || err != nil {
+		t.Fatalf("Get() on empty cache = %v, %v, want miss", got, err)
+	}
+
+	want := SolveWith(context.Background(), pieces, Options{})
+	if err := cache.Put(key, Options{}.String(), pieces, want); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	got, err := cache.Get(key, pieces)
+	if err != nil || got == nil {
+		t.Fatalf("Get() = %v, %v, want hit", got, err)
+	}
+	if got.Board.String() != want.Board.String() {
+		t.Errorf("Get() board:\n%s\nwant:\n%s", got.Board, want.Board)
+	}
+	if len(got.Sizes) != len(want.Sizes) {
+		t.Fatalf("Get() sizes = %+v, want %+v", got.Sizes, want.Sizes)
+	}
+	last, wantLast := got.Sizes[len(got.Sizes)-1], want.Sizes[len(want.Sizes)-1]
+	wantLast.BoundsTime, wantLast.SearchTime = 0, 0 // Timings describe the original run; not cached
+	if last != wantLast {
+		t.Errorf("Get() sizes = %+v, want %+v", got.Sizes, want.Sizes)
+	}
+}
+
+// TestCache_RejectsBadEntries checks that entries are verified before being trusted.
+func TestCache_RejectsBadEntries(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:6]
+	result := SolveWith(context.Background(), pieces, Options{})
+	solution := result.Board.String()
+
+	tests := []struct {
+		name string
+		edit func(entry string) string
+	}{
+		{"not json", func(string) string { return "{" }},
+		{"moved piece", func(entry string) string { // Swap two labels: shapes no longer match
+			return strings.Replace(entry, `"A`, `"B`, 1)
+		}},
+		{"other puzzle", func(entry string) string {
+			return strings.Replace(entry, `"shapes": [`, `"shapes": [99, `, 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &Cache{Dir: t.TempDir()}
+			key := CacheKey(pieces, "test")
+			if err := cache.Put(key, "test", pieces, result); err != nil {
+				t.Fatalf("Put() error: %v", err)
+			}
+			data, err := os.ReadFile(cache.path(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), strings.SplitN(solution, "\n", 2)[0]) {
+				t.Fatalf("entry does not hold the board:\n%s", data)
+			}
+			if err := os.WriteFile(cache.path(key), []byte(tt.edit(string(data))), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := cache.Get(key, pieces); got != nil || err == nil {
+				t.Errorf("Get() = %v, %v, want error", got, err)
+			}
+		})
+	}
+}
+
+// TestCache_PutUnfinished checks that timed-out results are refused.
+func TestCache_PutUnfinished(t *testing.T) {
+	cache := &Cache{Dir: t.TempDir()}
+	if err := cache.Put("k", "test", logPieces, &Result{Timeout: true}); err == nil {
+		t.Error("Put(timeout) succeeded, want error")
+	}
+}
+
+// TestCacheKey checks that keys depend on shapes, their order and the configuration.
+func TestCacheKey(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:4]
+	swapped := []*Tetromino{pieces[1], pieces[0], pieces[2], pieces[3]}
+	base := CacheKey(pieces, Options{}.String())
+
+	if again := CacheKey(parsePiecesFromString(t, hardExample)[:4], Options{}.String()); again != base {
+		t.Errorf("CacheKey() not deterministic: %s vs %s", base, again)
+	}
+	others := map[string]string{
+		"swapped pieces": CacheKey(swapped, Options{}.String()),
+		"fewer pieces":   CacheKey(pieces[:3], Options{}.String()),
+		"sat backend":    CacheKey(pieces, Options{Backend: SAT}.String()),
+		"canonical":      CacheKey(pieces, Options{Canonical: true}.String()),
+		"seed":           CacheKey(pieces, Options{Restarts: true, Seed: 1}.String()),
+	}
+	for name, key := range others {
+		if key == base {
+			t.Errorf("%s: same key as the default", name)
+		}
+	}
+}
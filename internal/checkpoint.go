@@ -0,0 +1,106 @@
+// Package internal saves and restores the position of a running search.
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// checkpointCheckNodes is how many placements pass between clock reads when
+// periodic checkpoints are enabled.
+const checkpointCheckNodes = 4096
+
+// Checkpoint is the frontier of the default search (ascending sizes, backtrack
+// backend, input order, no restarts, not canonical). Everything the search
+// would visit before Path, in its depth-first order, has been visited;
+// resuming re-enters Path and carries on from there, so the combined run
+// visits exactly the nodes of an uninterrupted one.
+type Checkpoint struct {
+	Shapes []int        `json:"shapes"` // ShapeID of each input piece, to reject a different puzzle
+	Done   []SizeReport `json:"done"`   // Reports of the sizes finished before Size
+	Size   int          `json:"size"`   // Board size being searched
+	Path   []int        `json:"path"`   // Per depth: cell index (row*Size+col) of the placement being explored
+	Nodes  int64        `json:"nodes"`  // Placements tried at Size so far
+}
+
+// resumable reports whether opts select the search that checkpoints describe.
+func resumable(opts Options) bool {
+	return !opts.Canonical && !opts.Restarts && opts.Backend == Backtrack && opts.Search == Ascending && opts.Order == InputOrder
+}
+
+// WriteCheckpoint writes the checkpoint as JSON.
+func WriteCheckpoint(w io.Writer, cp *Checkpoint) error {
+	enc := json.NewEncoder(w) // Encode straight to the writer
+	enc.SetIndent("", "  ")   // Readable and editable by hand
+	return enc.Encode(cp)
+}
+
+// ReadCheckpoint reads a checkpoint written by WriteCheckpoint and checks that
+// it belongs to these pieces and that its path is a legal placement sequence.
+func ReadCheckpoint(r io.Reader, pieces []*Tetromino) (*Checkpoint, error) {
+	var cp Checkpoint
+	if err := json.NewDecoder(r).Decode(&cp); err != nil { // Malformed JSON
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	if len(cp.Shapes) != len(pieces) { // Different number of pieces
+		return nil, fmt.Errorf("checkpoint is for %d pieces, puzzle has %d", len(cp.Shapes), len(pieces))
+	}
+	for i, p := range pieces { // Same shapes in the same order
+		if cp.Shapes[i] != ShapeID(p.Coords) { // A piece differs
+			return nil, fmt.Errorf("checkpoint does not match piece %c", p.Label)
+		}
+	}
+	if cp.Size < minSize(pieces) || len(cp.Path) > len(pieces) { // Size too small, or deeper than the pieces
+		return nil, fmt.Errorf("checkpoint position is out of range")
+	}
+
+	b := NewBoard(cp.Size)             // Empty board of the saved size
+	for depth, cell := range cp.Path { // Replay the path to make sure the search can re-enter it
+		row, col := cell/cp.Size, cell%cp.Size // Cell index back to coordinates
+		if cell < 0 || row >= cp.Size || !b.CanPlace(pieces[depth], row, col) {
+			return nil, fmt.Errorf("checkpoint places piece %c illegally", pieces[depth].Label)
+		}
+		b.Place(pieces[depth], row, col) // Place and go one level deeper
+	}
+	return &cp, nil
+}
+
+// newCheckpoint captures the frontier of s on a board of the given size.
+func newCheckpoint(pieces []*Tetromino, done []SizeReport, size int, path []int, nodes int64) *Checkpoint {
+	cp := &Checkpoint{
+		Shapes: make([]int, len(pieces)),        // Filled in below
+		Done:   append([]SizeReport{}, done...), // Copy: the caller keeps appending
+		Size:   size,                            // Board size being searched
+		Path:   append([]int{}, path...),        // The search keeps mutating its own slice
+		Nodes:  nodes,                           // Placements tried so far
+	}
+	for i, p := range pieces { // Fingerprint every piece
+		cp.Shapes[i] = ShapeID(p.Coords) // Shape of piece i
+	}
+	return cp
+}
+
+// maybeSave reports the frontier if a checkpoint is due. Called on entering a
+// node, where the path is exactly the frontier.
+func (s *search) maybeSave() {
+	if s.save == nil || s.resume != nil || s.nodes < s.nextCheck { // Disabled, not at the frontier yet, or not due
+		return
+	}
+	s.nextCheck = s.nodes + checkpointCheckNodes // Next clock read
+	if time.Since(s.saved) < s.every {           // Too soon since the last save
+		return
+	}
+	s.saved = time.Now()    // Restart the interval
+	s.save(s.path, s.nodes) // Hand the frontier to the caller
+}
+
+// frontier returns the path a checkpoint of s should record.
+func (s *search) frontier() []int {
+	if s.resume != nil { // Stopped while re-entering a checkpoint: its path is still the frontier
+		return s.resume
+	}
+	return s.path
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSolveWith_ResumeMatchesUninterrupted stops the search at every periodic
+// checkpoint and resumes it from the saved file until it finishes.
+func TestSolveWith_ResumeMatchesUninterrupted(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:11] // About 21000 nodes at 7x7
+	want := Solve(context.Background(), pieces)
+
+	var cp *Checkpoint
+	stops := 0
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := SolveWith(ctx, pieces, Options{
+			Resume:          cp,
+			CheckpointEvery: time.Nanosecond,                // Every checkpointCheckNodes placements
+			OnCheckpoint:    func(*Checkpoint) { cancel() }, // Stop as soon as one is taken
+		})
+		cancel()
+		if !result.Timeout {
+			if got := result.Board.String(); got != want.Board.String() {
+				t.Errorf("resumed board =\n%s\nwant\n%s", got, want.Board)
+			}
+			if len(result.Sizes) != len(want.Sizes) {
+				t.Fatalf("resumed sizes = %+v, want %+v", result.Sizes, want.Sizes)
+			}
+			for i := range want.Sizes {
+				if result.Sizes[i].Outcome != want.Sizes[i].Outcome || result.Sizes[i].Nodes != want.Sizes[i].Nodes {
+					t.Errorf("Sizes[%d] = %+v, want %+v", i, result.Sizes[i], want.Sizes[i])
+				}
+			}
+			break
+		}
+
+		stops++
+		if result.Checkpoint == nil {
+			t.Fatal("cancelled SolveWith() returned no checkpoint")
+		}
+		var buf bytes.Buffer // Round trip through the file format
+		if err := WriteCheckpoint(&buf, result.Checkpoint); err != nil {
+			t.Fatalf("WriteCheckpoint() error = %v", err)
+		}
+		var err error
+		if cp, err = ReadCheckpoint(&buf, pieces); err != nil {
+			t.Fatalf("ReadCheckpoint() error = %v", err)
+		}
+	}
+
+	if stops < 2 {
+		t.Errorf("search stopped %d times, want at least 2", stops)
+	}
+}
+
+func TestSolveWith_CancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := SolveWith(ctx, logPieces, Options{})
+	if cp := result.Checkpoint; cp == nil || cp.Size != 3 || len(cp.Path) != 0 {
+		t.Errorf("Checkpoint = %+v, want fresh start at size 3", cp)
+	}
+
+	result = SolveWith(ctx, logPieces, Options{Canonical: true})
+	if result.Checkpoint != nil {
+		t.Errorf("canonical Checkpoint = %+v, want nil", result.Checkpoint)
+	}
+}
+
+func TestReadCheckpoint_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", "size 4"},
+		{"wrong piece count", `{"shapes": [0], "size": 4, "path": []}`},
+		{"wrong shapes", `{"shapes": [-1, -1], "size": 4, "path": []}`},
+		{"size too small", `{"shapes": [SHAPES], "size": 2, "path": []}`},
+		{"path too long", `{"shapes": [SHAPES], "size": 4, "path": [0, 2, 8]}`},
+		{"overlapping path", `{"shapes": [SHAPES], "size": 4, "path": [0, 0]}`},
+		{"off the board", `{"shapes": [SHAPES], "size": 4, "path": [15]}`},
+	}
+
+	shapes := fmt.Sprintf("%d, %d", ShapeID(logPieces[0].Coords), ShapeID(logPieces[1].Coords))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := strings.Replace(tt.input, "SHAPES", shapes, 1)
+			if _, err := ReadCheckpoint(strings.NewReader(input), logPieces); err == nil {
+				t.Errorf("ReadCheckpoint(%s) error = nil, want error", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Package internal encodes the packing problem as SAT in DIMACS CNF format.
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsatisfiable is returned when a SAT solver's output reports no model.
+var ErrUnsatisfiable = errors.New("formula is unsatisfiable")
+
+// CNF encodes "the pieces fit on a Size×Size board" as a boolean formula.
+// Variable v (1-based) is true when Placements[v-1] is used. Clauses require
+// exactly one placement per piece and at most one placement per cell. When the
+// pieces exactly fill the board, every cell must also be covered; those clauses
+// are implied but let solvers propagate much earlier.
+type CNF struct {
+	Size       int
+	Placements []Placement // Placement chosen by each variable
+	Clauses    [][]int     // Each clause is a disjunction of literals (negative = negated)
+
+	pieces []*Tetromino    // Input pieces, for checking decoded boards
+	seen   map[string]bool // Clauses already added, so overlaps sharing several cells yield one clause
+}
+
+// EncodeCNF builds the placement encoding for one board size. A piece with no
+// legal placement yields an empty clause, making the formula unsatisfiable.
+func EncodeCNF(pieces []*Tetromino, size int) *CNF {
+	f := &CNF{Size: size, pieces: pieces, seen: make(map[string]bool)} // Empty formula for this size
+	empty := NewBoard(size)                                            // Legality checks only; nothing is placed
+	covering := make([][]int, size*size)                               // Cell index -> variables whose placement covers it
+
+	for _, p := range pieces { // One group of variables per piece
+		var vars []int                    // This piece's placement variables
+		for row := 0; row < size; row++ { // Try each row position
+			for col := 0; col < size; col++ { // Try each column position
+				if !empty.CanPlace(p, row, col) { // Off the board
+					continue
+				}
+				f.Placements = append(f.Placements, Placement{Piece: p, Row: row, Col: col}) // New variable for this placement
+				v := len(f.Placements)                                                       // Its 1-based number
+				vars = append(vars, v)                                                       // Belongs to this piece
+				for _, c := range p.Coords {                                                 // Every cell of the piece
+					cell := (row+c.Row)*size + col + c.Col     // Cell index on the board
+					covering[cell] = append(covering[cell], v) // Placement covers the cell
+				}
+			}
+		}
+		f.addClause(vars) // At least one placement
+		f.atMostOne(vars) // At most one placement
+	}
+
+	for _, vars := range covering { // Every cell
+		f.atMostOne(vars) // Pieces may not overlap
+	}
+	if 4*len(pieces) == size*size { // No empty cells: each cell is covered by some placement
+		for _, vars := range covering { // Every cell
+			f.addClause(vars) // At least one placement covers it
+		}
+	}
+	return f
+}
+
+// addClause appends a clause unless an identical one was already added.
+func (f *CNF) addClause(lits []int) {
+	key := fmt.Sprint(lits) // Callers build literals in a fixed order, so equal clauses print equally
+	if f.seen[key] {        // Duplicate clause
+		return
+	}
+	f.seen[key] = true                  // Remember the clause
+	f.Clauses = append(f.Clauses, lits) // Append it
+}
+
+// atMostOne adds the pairwise encoding: no two of vars are both true.
+func (f *CNF) atMostOne(vars []int) {
+	for i := 0; i < len(vars); i++ { // Every variable
+		for j := i + 1; j < len(vars); j++ { // Paired with each later one
+			f.addClause([]int{-vars[i], -vars[j]}) // vars are ascending, so each pair is built one way
+		}
+	}
+}
+
+// NumVars returns the number of variables in the formula.
+func (f *CNF) NumVars() int {
+	return len(f.Placements) // One variable per placement
+}
+
+// WriteDIMACS writes the formula in DIMACS CNF format, with comment lines
+// mapping each variable to its placement.
+func (f *CNF) WriteDIMACS(w io.Writer) error {
+	bw := bufio.NewWriter(w)                                                                // Buffer the many small writes
+	fmt.Fprintf(bw, "c tetris-optimizer placement encoding, board %dx%d\n", f.Size, f.Size) // Header comment
+	for i, p := range f.Placements {                                                        // One comment per variable
+		fmt.Fprintf(bw, "c var %d = %c at %d %d\n", i+1, p.Piece.Label, p.Row, p.Col)
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", f.NumVars(), len(f.Clauses)) // Problem line
+	for _, clause := range f.Clauses {                            // One line per clause
+		for _, lit := range clause { // Every literal
+			bw.WriteString(strconv.Itoa(lit))
+			bw.WriteByte(' ')
+		}
+		bw.WriteString("0\n") // Terminate the clause
+	}
+	return bw.Flush() // Push the buffer out
+}
+
+// Decode turns a satisfying assignment into a board. The model lists literals
+// as printed by SAT solvers; variables not listed are false. The result is
+// checked with Verify, so a wrong model is reported rather than rendered.
+func (f *CNF) Decode(model []int) (*Board, error) {
+	b := NewBoard(f.Size)       // Empty board of the formula's size
+	for _, lit := range model { // Every literal of the model
+		if lit <= 0 { // False or terminator
+			continue
+		}
+		if lit > f.NumVars() { // No such variable
+			return nil, fmt.Errorf("model variable %d out of range (formula has %d)", lit, f.NumVars())
+		}
+		p := f.Placements[lit-1]                // Placement chosen by the variable
+		if !b.CanPlace(p.Piece, p.Row, p.Col) { // Overlap: model violates at-most-one per cell
+			return nil, fmt.Errorf("model places piece %c over another piece", p.Piece.Label)
+		}
+		b.Place(p.Piece, p.Row, p.Col) // Place the piece
+	}
+
+	if err := Verify(f.pieces, b); err != nil { // Missing or duplicated pieces
+		return nil, err
+	}
+	return b, nil
+}
+
+// ParseModel reads a SAT solver's output: "s" status lines and "v" value lines
+// in the SAT competition format, or MiniSat's result file (a SAT/UNSAT line
+// followed by bare literals). Comment lines ("c") are skipped. Returns
+// ErrUnsatisfiable if the status says so.
+func ParseModel(r io.Reader) ([]int, error) {
+	lines, err := readLines(r) // Lines without endings
+	if err != nil {
+		return nil, err
+	}
+
+	var model []int              // Literals collected so far
+	for i, line := range lines { // Every line
+		fields := strings.Fields(line)            // Split on whitespace
+		if len(fields) == 0 || fields[0] == "c" { // Blank or comment
+			continue
+		}
+		switch fields[0] { // Classify by the first field
+		case "UNSAT": // MiniSat result file
+			return nil, ErrUnsatisfiable
+		case "SAT": // MiniSat result file
+			continue
+		case "s": // Status line
+			if strings.Contains(line, "UNSAT") {
+				return nil, ErrUnsatisfiable
+			}
+			continue
+		case "v": // Value line
+			fields = fields[1:]
+		}
+		for _, field := range fields { // Every remaining field
+			lit, err := strconv.Atoi(field) // Parse the literal
+			if err != nil {
+				return nil, fmt.Errorf("invalid literal %q at line %d", field, i+1)
+			}
+			model = append(model, lit) // Add it to the model
+		}
+	}
+	return model, nil
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// satisfies reports whether the assignment (index v = value of variable v) satisfies every clause.
+func satisfies(f *CNF, assign []bool) bool {
+	for _, clause := range f.Clauses {
+		ok := false
+		for _, lit := range clause {
+			if (lit > 0 && assign[lit]) || (lit < 0 && !assign[-lit]) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// TestEncodeCNF_BruteForce checks satisfiability against the search on tiny instances.
+func TestEncodeCNF_BruteForce(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantSAT bool
+	}{
+		{3, false}, // L and J cannot share 3x3
+		{4, true},
+	}
+
+	for _, tt := range tests {
+		f := EncodeCNF(logPieces, tt.size)
+		if f.NumVars() > 20 {
+			t.Fatalf("size %d: %d variables, too many to brute force", tt.size, f.NumVars())
+		}
+
+		found := false
+		for mask := 0; mask < 1<<f.NumVars() && !found; mask++ {
+			assign := make([]bool, f.NumVars()+1)
+			var model []int
+			for v := 1; v <= f.NumVars(); v++ {
+				if mask&(1<<(v-1)) != 0 {
+					assign[v] = true
+					model = append(model, v)
+				}
+			}
+			if satisfies(f, assign) {
+				found = true
+				if _, err := f.Decode(model); err != nil {
+					t.Errorf("size %d: Decode(satisfying model) error = %v", tt.size, err)
+				}
+			}
+		}
+		if found != tt.wantSAT {
+			t.Errorf("size %d: satisfiable = %v, want %v", tt.size, found, tt.wantSAT)
+		}
+	}
+}
+
+// TestCNF_SolverSolutionSatisfies maps a solver board to a model and checks it.
+func TestCNF_SolverSolutionSatisfies(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+		{Label: 'C', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+	}
+	result := Solve(context.Background(), pieces)
+	f := EncodeCNF(pieces, result.Board.Size)
+
+	assign := make([]bool, f.NumVars()+1)
+	var model []int
+	for _, want := range result.Board.Placements() {
+		for i, p := range f.Placements {
+			if p.Piece.Label == want.Piece.Label && p.Row == want.Row && p.Col == want.Col {
+				assign[i+1] = true
+				model = append(model, i+1)
+			}
+		}
+	}
+	if !satisfies(f, assign) {
+		t.Fatal("solver solution does not satisfy the encoding")
+	}
+
+	b, err := f.Decode(model)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if b.String() != result.Board.String() {
+		t.Errorf("Decode() = %q, want %q", b.String(), result.Board.String())
+	}
+}
+
+func TestCNF_WriteDIMACS(t *testing.T) {
+	f := EncodeCNF(logPieces, 3)
+	var buf bytes.Buffer
+	if err := f.WriteDIMACS(&buf); err != nil {
+		t.Fatalf("WriteDIMACS() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "p cnf 4 ") { // 2 placements each for L and J in 3x3
+		t.Errorf("WriteDIMACS() header missing or wrong:\n%s", out)
+	}
+	body := out[strings.Index(out, "p cnf"):]                        // Clauses follow the header
+	if got := strings.Count(body, "\n") - 1; got != len(f.Clauses) { // One line per clause
+
+		t.Errorf("WriteDIMACS() wrote %d clauses, want %d", got, len(f.Clauses))
+	}
+}
+
+func TestCNF_DecodeErrors(t *testing.T) {
+	f := EncodeCNF(logPieces, 4)
+
+	tests := []struct {
+		name  string
+		model []int
+	}{
+		{"out of range", []int{f.NumVars() + 1}},
+		{"missing piece", []int{1}},
+		{"empty model", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.Decode(tt.model); err == nil {
+				t.Error("Decode() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr error
+	}{
+		{"competition format", "c comment\ns SATISFIABLE\nv 1 -2 3\nv -4 0\n", []int{1, -2, 3, -4, 0}, nil},
+		{"bare literals", "1 -2 3 0\n", []int{1, -2, 3, 0}, nil},
+		{"minisat result file", "SAT\n-1 2 0\n", []int{-1, 2, 0}, nil},
+		{"unsatisfiable", "s UNSATISFIABLE\n", nil, ErrUnsatisfiable},
+		{"minisat unsatisfiable", "UNSAT\n", nil, ErrUnsatisfiable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseModel(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseModel() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseModel() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseModel() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseModel(strings.NewReader("v 1 x\n")); err == nil {
+		t.Error("ParseModel() expected error for invalid literal")
+	}
+}
@@ -0,0 +1,104 @@
+// Package internal formats machine-readable progress events.
+package internal
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is one progress event, written as a line of JSON by EventWriter.
+// Fields that do not apply to an event are left out. Kinds:
+//
+//   - "started": solving began; Pieces is set.
+//   - "size_started": work on Size began.
+//   - "size_ruled_out": CheckBounds ruled Size out; Bound says why.
+//   - "size_exhausted": the search of Size finished without a board.
+//   - "size_cancelled": the search of Size was stopped by the timeout or an interrupt.
+//   - "solution_found": a board of Size was found; with an anytime search
+//     smaller ones may follow.
+//   - "heartbeat": periodic statistics of the search in progress.
+//   - "finished": the run ended; Outcome is "solved", "no_solution",
+//     "timeout", "interrupted" or "error" (with Error).
+type Event struct {
+	Event          string   `json:"event"`
+	Elapsed        float64  `json:"elapsed_seconds"`            // Since the EventWriter was created
+	Pieces         int      `json:"pieces,omitempty"`           // Number of input pieces
+	Size           int      `json:"size,omitempty"`             // Board size the event is about
+	Bound          string   `json:"bound,omitempty"`            // Argument that ruled Size out
+	Nodes          int64    `json:"nodes,omitempty"`            // Placements tried at Size (SAT decisions)
+	SearchSeconds  float64  `json:"search_seconds,omitempty"`   // Time spent searching Size
+	Depth          int      `json:"depth,omitempty"`            // Pieces currently placed
+	MaxDepth       int      `json:"max_depth,omitempty"`        // Most pieces placed at once at Size
+	TotalNodes     int64    `json:"total_nodes,omitempty"`      // Placements tried at all sizes
+	NodesPerSecond float64  `json:"nodes_per_second,omitempty"` // Since the previous heartbeat
+	Explored       *float64 `json:"explored,omitempty"`         // Estimated fraction of Size's search done
+	ETA            *float64 `json:"eta_seconds,omitempty"`      // Estimated time to exhaust Size
+	Outcome        string   `json:"outcome,omitempty"`          // How the run ended
+	Cached         bool     `json:"cached,omitempty"`           // Answer taken from the result cache
+	Error          string   `json:"error,omitempty"`            // Why the run failed
+}
+
+// SizeEvent returns the event reporting the end of work on a size.
+func SizeEvent(r SizeReport) Event {
+	e := Event{Size: r.Size, Nodes: r.Nodes, SearchSeconds: r.SearchTime.Seconds()} // Fields shared by every kind
+	switch r.Outcome {                                                              // Kind follows the outcome
+	case Solved: // Board found
+		e.Event = "solution_found"
+	case RuledOut: // Bounds ruled the size out
+		e.Event, e.Bound = "size_ruled_out", r.Bound.String()
+	case Exhausted: // Searched to the end
+		e.Event = "size_exhausted"
+	case Failed: // Search broke down
+		e.Event, e.Error = "size_failed", r.Err.Error()
+	default: // Timeout or interrupt
+		e.Event = "size_cancelled"
+	}
+	return e
+}
+
+// HeartbeatEvent returns a heartbeat with the statistics in snap; rate is the
+// node rate to report.
+func HeartbeatEvent(snap ProgressSnapshot, rate float64) Event {
+	e := Event{Event: "heartbeat", Size: snap.Size, Depth: snap.Depth, MaxDepth: snap.MaxDepth, // Current position of the search
+		Nodes: snap.Nodes, TotalNodes: snap.Total, NodesPerSecond: rate}
+	if eta, ok := snap.ETA(); ok { // Estimate available
+		explored, secs := snap.Explored, eta.Seconds() // Copies for the pointer fields
+		e.Explored, e.ETA = &explored, &secs
+	}
+	return e
+}
+
+// EventWriter writes events as newline-delimited JSON. It is safe for
+// concurrent use.
+type EventWriter struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+	err   error // First write error
+}
+
+// NewEventWriter returns an EventWriter writing to w. Elapsed times count from now.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{enc: json.NewEncoder(w), start: time.Now()} // Elapsed times count from now
+}
+
+// Write sets e.Elapsed and writes e on one line. After a write error nothing
+// more is written; Err reports it.
+func (ew *EventWriter) Write(e Event) {
+	ew.mu.Lock() // Writers may be on different goroutines
+	defer ew.mu.Unlock()
+	if ew.err != nil { // Earlier write failed: stay silent
+		return
+	}
+	e.Elapsed = time.Since(ew.start).Seconds() // Stamp the event
+	ew.err = ew.enc.Encode(e)                  // One line of JSON
+}
+
+// Err returns the first error met while writing.
+func (ew *EventWriter) Err() error {
+	ew.mu.Lock() // Guard the error field
+	defer ew.mu.Unlock()
+	return ew.err
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSizeEvent(t *testing.T) {
+	bound := &Infeasibility{Size: 4, Bound: "area", Reason: "needs 20 cells"}
+	tests := []struct {
+		report SizeReport
+		want   string
+	}{
+		{SizeReport{Size: 5, Outcome: Solved, Nodes: 10}, "solution_found"},
+		{SizeReport{Size: 4, Outcome: RuledOut, Bound: bound}, "size_ruled_out"},
+		{SizeReport{Size: 4, Outcome: Exhausted, Nodes: 7}, "size_exhausted"},
+		{SizeReport{Size: 6, Outcome: Cancelled, Nodes: 3}, "size_cancelled"},
+		{SizeReport{Size: 6, Outcome: Failed, Nodes: 3, Err: errors.New("bad model")}, "size_failed"},
+	}
+	for _, tt := range tests {
+		e := SizeEvent(tt.report)
+		if e.Event != tt.want || e.Size != tt.report.Size || e.Nodes != tt.report.Nodes {
+			t.Errorf("SizeEvent(%+v) = %+v, want %s of size %d", tt.report, e, tt.want, tt.report.Size)
+		}
+		if (e.Bound != "") != (tt.report.Bound != nil) {
+			t.Errorf("SizeEvent(%+v) bound = %q", tt.report, e.Bound)
+		}
+		if (e.Error != "") != (tt.report.Err != nil) {
+			t.Errorf("SizeEvent(%+v) error = %q", tt.report, e.Error)
+		}
+	}
+}
+
+func TestHeartbeatEvent(t *testing.T) {
+	snap := ProgressSnapshot{Size: 7, Depth: 3, MaxDepth: 9, Nodes: 100, Total: 150, Explored: -1}
+	e := HeartbeatEvent(snap, 42)
+	if e.Event != "heartbeat" || e.Size != 7 || e.Depth != 3 || e.MaxDepth != 9 || e.Nodes != 100 || e.TotalNodes != 150 || e.NodesPerSecond != 42 {
+		t.Errorf("HeartbeatEvent() = %+v", e)
+	}
+	if e.Explored != nil || e.ETA != nil {
+		t.Errorf("HeartbeatEvent() without an estimate = %+v, want no explored or ETA", e)
+	}
+
+	snap.Explored, snap.Started = 0.5, time.Now().Add(-time.Second)
+	e = HeartbeatEvent(snap, 42)
+	if e.Explored == nil || *e.Explored != 0.5 || e.ETA == nil || *e.ETA < 1 {
+		t.Errorf("HeartbeatEvent() with an estimate = %+v, want explored 0.5 and an ETA of about 1s", e)
+	}
+}
+
+// TestEventWriter checks concurrent writes come out as whole JSON lines.
+func TestEventWriter(t *testing.T) {
+	var out bytes.Buffer
+	ew := NewEventWriter(&out)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(size int) {
+			defer wg.Done()
+			ew.Write(Event{Event: "size_started", Size: size})
+		}(i)
+	}
+	wg.Wait()
+	if err := ew.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("wrote %d lines, want 20:\n%s", len(lines), out.String())
+	}
+	for _, line := range lines {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		if e["event"] != "size_started" || e["elapsed_seconds"] == nil || e["size"] == nil || len(e) != 3 {
+			t.Errorf("line %q, want event, elapsed_seconds and size only", line)
+		}
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestEventWriter_Error(t *testing.T) {
+	w := &failingWriter{}
+	ew := NewEventWriter(w)
+	ew.Write(Event{Event: "started"})
+	ew.Write(Event{Event: "finished"})
+	if err := ew.Err(); err == nil || w.writes != 1 {
+		t.Errorf("Err() = %v after %d writes, want the first error and no more writes", err, w.writes)
+	}
+}
@@ -0,0 +1,33 @@
+// Package internal holds the bitmap font for labels in raster images.
+package internal
+
+// glyphs are 5x7 bitmaps of the piece labels, one space-separated string of
+// rows per letter, '#' for ink. The image packages bring no fonts of their own.
+var glyphs = map[byte]string{
+	'A': ".###. #...# #...# ##### #...# #...# #...#",
+	'B': "####. #...# #...# ####. #...# #...# ####.",
+	'C': ".###. #...# #.... #.... #.... #...# .###.",
+	'D': "####. #...# #...# #...# #...# #...# ####.",
+	'E': "##### #.... #.... ####. #.... #.... #####",
+	'F': "##### #.... #.... ####. #.... #.... #....",
+	'G': ".###. #...# #.... #.### #...# #...# .####",
+	'H': "#...# #...# #...# ##### #...# #...# #...#",
+	'I': ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J': "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K': "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L': "#.... #.... #.... #.... #.... #.... #####",
+	'M': "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N': "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O': ".###. #...# #...# #...# #...# #...# .###.",
+	'P': "####. #...# #...# ####. #.... #.... #....",
+	'Q': ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R': "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S': ".#### #.... #.... .###. ....# ....# ####.",
+	'T': "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U': "#...# #...# #...# #...# #...# #...# .###.",
+	'V': "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W': "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X': "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y': "#...# #...# .#.#. ..#.. ..#.. ..#.. ..#..",
+	'Z': "##### ....# ...#. ..#.. .#... #.... #####",
+}
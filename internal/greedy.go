@@ -0,0 +1,56 @@
+// Package internal implements a fast constructive packer for upper bounds.
+package internal
+
+// Greedy packs the pieces without backtracking. It fills cells in row-major
+// order (top-left fill, the mirror image of the classic bottom-left rule): the
+// first empty cell is covered by the first remaining piece that fits there, or
+// left as a hole if none does. The smallest size where this succeeds is
+// returned; it is a valid board, often minimal or close to it, found in
+// polynomial time.
+func Greedy(pieces []*Tetromino) *Board {
+	slots := slotBoard(pieces)                                  // Fallback that always works
+	for size := LowerBound(pieces); size < slots.Size; size++ { // Smallest promising size first
+		if b := greedyFill(pieces, size); b != nil { // Greedy pass succeeded
+			return b
+		}
+	}
+	return slots
+}
+
+// greedyFill runs one greedy pass on a size x size board.
+// Returns nil if it leaves more holes than the board can spare.
+func greedyFill(pieces []*Tetromino, size int) *Board {
+	b := NewBoard(size)                       // Empty board
+	left := append([]*Tetromino{}, pieces...) // Pieces not yet placed, in input order
+	holes := size*size - 4*len(pieces)        // Empty cells the board can afford
+
+	for r := 0; r < size && len(left) > 0; r++ { // Row-major fill
+		for c := 0; c < size && len(left) > 0; c++ { // Each cell of the row
+			if b.Grid[r][c] != '.' { // Covered by an earlier piece
+				continue
+			}
+
+			placed := false          // No piece fits this cell yet
+			for i, p := range left { // First remaining piece that fits
+				a := firstCell(p.Coords)             // Anchor the piece's first cell on the empty cell
+				if b.CanPlace(p, r-a.Row, c-a.Col) { // Fits with its first cell here
+					b.Place(p, r-a.Row, c-a.Col)           // Place the piece
+					left = append(left[:i], left[i+1:]...) // Remove it from the remaining list
+					placed = true
+					break
+				}
+			}
+			if !placed { // Cell left empty
+				holes--        // One more hole
+				if holes < 0 { // Too much waste for this size
+					return nil
+				}
+			}
+		}
+	}
+
+	if len(left) > 0 { // Ran out of board
+		return nil
+	}
+	return b
+}
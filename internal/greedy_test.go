@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestGreedy(t *testing.T) {
+	o := []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	tests := []struct {
+		name     string
+		pieces   []*Tetromino
+		wantSize int // Greedy result size, not necessarily the minimum
+	}{
+		{"no pieces", nil, 0},
+		{"four O", []*Tetromino{{Label: 'A', Coords: o}, {Label: 'B', Coords: o}, {Label: 'C', Coords: o}, {Label: 'D', Coords: o}}, 4},
+		{"L and J", logPieces, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Greedy(tt.pieces)
+			if err := Verify(tt.pieces, b); err != nil {
+				t.Fatalf("Greedy() invalid: %v\n%s", err, b)
+			}
+			if b.Size < LowerBound(tt.pieces) {
+				t.Errorf("Greedy() size = %d, below LowerBound %d", b.Size, LowerBound(tt.pieces))
+			}
+			if tt.wantSize > 0 && b.Size != tt.wantSize {
+				t.Errorf("Greedy() size = %d, want %d\n%s", b.Size, tt.wantSize, b)
+			}
+		})
+	}
+}
+
+// TestGreedy_HardExample checks that the packer stays close to the 7x7 minimum.
+func TestGreedy_HardExample(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+
+	b := Greedy(pieces)
+	if err := Verify(pieces, b); err != nil {
+		t.Fatalf("Greedy() invalid: %v\n%s", err, b)
+	}
+	if b.Size > 9 {
+		t.Errorf("Greedy() size = %d, want at most 9\n%s", b.Size, b)
+	}
+}
@@ -0,0 +1,214 @@
+// Package internal renders boards as raster images.
+package internal
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultCellSize is the side of a board cell in image pixels.
+	DefaultCellSize = 40
+	// DefaultBorder is the thickness of piece outlines in image pixels.
+	DefaultBorder = 2
+)
+
+// Palette holds the colours of a rendered image.
+type Palette struct {
+	Pieces map[byte][2]color.RGBA // Per tetromino family (0: not a tetromino): normal shade, shade for touching pieces of one family
+	Empty  color.RGBA             // Empty cells
+	Border color.RGBA             // Outlines and grid lines
+	Text   color.RGBA             // Piece labels
+}
+
+// Palettes are the named colour schemes for images. "soft" is the default.
+var Palettes = map[string]Palette{
+	"soft": { // Tetris hues, softened so black labels stay readable
+		Pieces: map[byte][2]color.RGBA{
+			'I': {rgb(0x4dd0e1), rgb(0xb2ebf2)}, // Cyan
+			'O': {rgb(0xffd54f), rgb(0xffecb3)}, // Yellow
+			'T': {rgb(0xba68c8), rgb(0xe1bee7)}, // Purple
+			'S': {rgb(0x81c784), rgb(0xc8e6c9)}, // Green
+			'Z': {rgb(0xe57373), rgb(0xffcdd2)}, // Red
+			'J': {rgb(0x64b5f6), rgb(0xbbdefb)}, // Blue
+			'L': {rgb(0xffb74d), rgb(0xffe0b2)}, // Orange
+			0:   {rgb(0xbdbdbd), rgb(0xe0e0e0)}, // Grey
+		},
+		Empty: rgb(0xf4f4f4), Border: rgb(0x000000), Text: rgb(0x000000),
+	},
+	"classic": { // Saturated arcade colours on a dark well
+		Pieces: map[byte][2]color.RGBA{
+			'I': {rgb(0x00f0f0), rgb(0x80f8f8)},
+			'O': {rgb(0xf0f000), rgb(0xf8f880)},
+			'T': {rgb(0xa000f0), rgb(0xd080f8)},
+			'S': {rgb(0x00f000), rgb(0x80f880)},
+			'Z': {rgb(0xf00000), rgb(0xf88080)},
+			'J': {rgb(0x0000f0), rgb(0x8080f8)},
+			'L': {rgb(0xf0a000), rgb(0xf8d080)},
+			0:   {rgb(0xa0a0a0), rgb(0xd0d0d0)},
+		},
+		Empty: rgb(0x202020), Border: rgb(0x000000), Text: rgb(0xffffff),
+	},
+	"gray": { // Print-friendly greys, one per family
+		Pieces: map[byte][2]color.RGBA{
+			'I': {rgb(0xa8a8a8), rgb(0xc8c8c8)},
+			'O': {rgb(0xd8d8d8), rgb(0xececec)},
+			'T': {rgb(0x989898), rgb(0xb8b8b8)},
+			'S': {rgb(0xc0c0c0), rgb(0xdcdcdc)},
+			'Z': {rgb(0xb0b0b0), rgb(0xd0d0d0)},
+			'J': {rgb(0x909090), rgb(0xb0b0b0)},
+			'L': {rgb(0xcccccc), rgb(0xe4e4e4)},
+			0:   {rgb(0x808080), rgb(0xa0a0a0)},
+		},
+		Empty: rgb(0xffffff), Border: rgb(0x000000), Text: rgb(0x000000),
+	},
+}
+
+// PaletteNames returns the names of Palettes, sorted.
+func PaletteNames() []string {
+	names := make([]string, 0, len(Palettes)) // Room for every name
+	for name := range Palettes {              // Map order is random
+		names = append(names, name)
+	}
+	sort.Strings(names) // Stable output for help and errors
+	return names
+}
+
+// rgb converts 0xrrggbb to an opaque colour.
+func rgb(v uint32) color.RGBA {
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+// ImageOptions controls Board.SVG and Board.Image. The zero value selects the
+// defaults, without labels or grid.
+type ImageOptions struct {
+	CellSize int      // Side of a cell in pixels (DefaultCellSize if zero)
+	Border   int      // Outline thickness in pixels (DefaultBorder if zero, none if negative)
+	Palette  *Palette // Colours (Palettes["soft"] if nil)
+	Labels   bool     // Write each piece's letter in its most central cell
+	Grid     bool     // Draw faint lines between all cells, not just around pieces
+}
+
+// withDefaults returns the options with zero fields replaced by defaults.
+func (o ImageOptions) withDefaults() ImageOptions {
+	if o.CellSize <= 0 { // Unset or nonsensical cell size
+		o.CellSize = DefaultCellSize
+	}
+	if o.Border == 0 { // Unset border
+		o.Border = DefaultBorder
+	}
+	if o.Border < 0 { // Negative: no outlines
+		o.Border = 0
+	}
+	if o.Palette == nil { // Unset palette
+		soft := Palettes["soft"] // Copy, so callers cannot change the default
+		o.Palette = &soft
+	}
+	return o
+}
+
+// Image renders the board as a raster image: pieces filled with the colour of
+// their family, outlined wherever neighbouring cells hold different labels.
+// The board is inset by half the border width so the frame is drawn in full.
+func (b *Board) Image(opts ImageOptions) *image.RGBA {
+	opts = opts.withDefaults()                             // Fill in unset fields
+	cs, w, pal := opts.CellSize, opts.Border, opts.Palette // Cell size, border width, colours
+	off := (w + 1) / 2                                     // Inset of the board's top-left corner
+	side := b.Size*cs + 2*off                              // Board plus the inset on both sides
+	img := image.NewRGBA(image.Rect(0, 0, side, side))     // Transparent until filled
+	fill := func(r image.Rectangle, c color.Color, op draw.Op) {
+		draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, op) // Paint a rectangle in one colour
+	}
+	cell := func(r, c int) image.Rectangle { // Pixel area of a cell
+		return image.Rect(off+c*cs, off+r*cs, off+(c+1)*cs, off+(r+1)*cs)
+	}
+
+	family, shade := b.pieceShades() // Colour choice per label
+	for r, row := range b.Grid {     // Iterate through each row
+		for c, label := range row { // Iterate through each cell
+			col := pal.Empty  // Empty unless a piece covers it
+			if label != '.' { // Covered by a piece
+				col = pal.Pieces[family[label]][shade[label]] // Family colour, in the piece's shade
+			}
+			fill(cell(r, c), col, draw.Src) // Cells never overlap
+		}
+	}
+
+	if opts.Grid { // Faint lines between all cells
+		faint := color.NRGBA{pal.Border.R, pal.Border.G, pal.Border.B, 0x26} // 15% opacity, as in SVG
+		for i := 1; i < b.Size; i++ {                                        // One horizontal and one vertical line per inner edge
+			fill(image.Rect(off, off+i*cs, off+b.Size*cs, off+i*cs+1), faint, draw.Over)
+			fill(image.Rect(off+i*cs, off, off+i*cs+1, off+b.Size*cs), faint, draw.Over)
+		}
+	}
+
+	if w > 0 { // Outlines, centred on the cell edges and long enough to close the corners
+		for r := 0; r <= b.Size; r++ { // Horizontal edges, top to bottom
+			for c := 0; c < b.Size; c++ { // Each cell along the edge
+				if b.at(r-1, c) != b.at(r, c) { // Labels differ across the edge
+					x, y := off+c*cs, off+r*cs
+					fill(image.Rect(x-w/2, y-w/2, x+cs-w/2+w, y-w/2+w), pal.Border, draw.Src)
+				}
+			}
+		}
+		for c := 0; c <= b.Size; c++ { // Vertical edges, left to right
+			for r := 0; r < b.Size; r++ { // Each cell along the edge
+				if b.at(r, c-1) != b.at(r, c) { // Labels differ across the edge
+					x, y := off+c*cs, off+r*cs
+					fill(image.Rect(x-w/2, y-w/2, x-w/2+w, y+cs-w/2+w), pal.Border, draw.Src)
+				}
+			}
+		}
+	}
+
+	if opts.Labels { // Letters asked for
+		scale := max(1, cs/16)         // Glyph pixel size: the 5x7 glyph takes under half the cell
+		cells, order := b.labelCells() // Cells of each piece, in label order
+		for _, label := range order {  // One letter per piece
+			glyph, ok := glyphs[label]
+			if !ok { // No glyph for this byte
+				continue
+			}
+			mid := centralCell(cells[label]) // Where the letter goes
+			at := cell(mid.Row, mid.Col)
+			x0 := at.Min.X + (cs-5*scale)/2 // Centre the glyph in the cell
+			y0 := at.Min.Y + (cs-7*scale)/2
+			for gy, line := range strings.Fields(glyph) { // Glyph rows, top to bottom
+				for gx, dot := range line { // Glyph columns, left to right
+					if dot == '#' { // Ink
+						fill(image.Rect(x0+gx*scale, y0+gy*scale, x0+(gx+1)*scale, y0+(gy+1)*scale), pal.Text, draw.Src)
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG encodes the board's Image as PNG.
+func (b *Board) WritePNG(w io.Writer, opts ImageOptions) error {
+	return png.Encode(w, b.Image(opts))
+}
+
+// centralCell returns the cell closest to the centre of the cells, the first
+// in the given order on ties. The centre itself may lie outside an L or T.
+func centralCell(cells []Point) Point {
+	var sumRow, sumCol int
+	for _, c := range cells { // Sum the coordinates
+		sumRow += c.Row
+		sumCol += c.Col
+	}
+	best, bestDist := cells[0], -1
+	for _, c := range cells { // Distances scaled by len(cells) to stay in integers
+		dr, dc := c.Row*len(cells)-sumRow, c.Col*len(cells)-sumCol
+		if dist := dr*dr + dc*dc; bestDist < 0 || dist < bestDist { // Closer than the best so far
+			best, bestDist = c, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestBoard_Image checks image size, cell colours, outlines and labels by pixel.
+func TestBoard_Image(t *testing.T) {
+	b, err := ParseBoard(strings.NewReader("A..B\nA..B\nAA.B\n...B\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+	soft, gray := Palettes["soft"], Palettes["gray"]
+
+	tests := []struct {
+		name   string
+		opts   ImageOptions
+		side   int
+		pixels map[[2]int]color.RGBA // (x, y) -> expected colour
+	}{
+		{
+			name: "defaults",
+			opts: ImageOptions{},
+			side: 4*DefaultCellSize + 2,
+			pixels: map[[2]int]color.RGBA{
+				{21, 21}:  soft.Pieces['L'][0], // Inside A, away from the middle
+				{141, 21}: soft.Pieces['I'][0], // Inside B
+				{61, 21}:  soft.Empty,
+				{0, 0}:    soft.Border, // Frame corner
+				{41, 21}:  soft.Border, // A's right edge
+				{81, 21}:  soft.Empty,  // No border between empty cells
+			},
+		},
+		{
+			name: "cell size, border and palette",
+			opts: ImageOptions{CellSize: 10, Border: 4, Palette: &gray},
+			side: 4*10 + 4,
+			pixels: map[[2]int]color.RGBA{
+				{7, 7}:  gray.Pieces['L'][0],
+				{1, 1}:  gray.Border,
+				{13, 7}: gray.Border, // 4px edge around x=12
+				{17, 7}: gray.Empty,
+			},
+		},
+		{
+			name: "no border",
+			opts: ImageOptions{Border: -1},
+			side: 4 * DefaultCellSize,
+			pixels: map[[2]int]color.RGBA{
+				{0, 0}:  soft.Pieces['L'][0],
+				{40, 0}: soft.Empty,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := b.Image(tt.opts)
+			if got := img.Bounds().Dx(); got != tt.side || img.Bounds().Dy() != tt.side {
+				t.Fatalf("Image() is %v, want %dx%d", img.Bounds(), tt.side, tt.side)
+			}
+			for at, want := range tt.pixels {
+				if got := img.RGBAAt(at[0], at[1]); got != want {
+					t.Errorf("pixel %v = %v, want %v", at, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestBoard_ImageLabels checks that labels put ink in the labelled cells only.
+func TestBoard_ImageLabels(t *testing.T) {
+	b, err := ParseBoard(strings.NewReader("A...\nA...\nA...\nA...\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+	soft := Palettes["soft"]
+	ink := func(opts ImageOptions, row int) int { // Text pixels inside a cell of column 0
+		img := b.Image(opts)
+		n := 0
+		for y := row*DefaultCellSize + 4; y < (row+1)*DefaultCellSize-2; y++ {
+			for x := 4; x < DefaultCellSize-2; x++ {
+				if img.RGBAAt(x, y) == soft.Text {
+					n++
+				}
+			}
+		}
+		return n
+	}
+
+	if n := ink(ImageOptions{}, 1); n != 0 {
+		t.Errorf("unlabelled image has %d text pixels", n)
+	}
+	if n := ink(ImageOptions{Labels: true}, 1); n == 0 { // centralCell of a vertical I
+		t.Error("labelled image has no text in the central cell")
+	}
+	if n := ink(ImageOptions{Labels: true}, 3); n != 0 {
+		t.Errorf("labelled image has %d text pixels outside the central cell", n)
+	}
+}
+
+// TestBoard_WritePNG checks that the PNG decodes to the rendered image.
+func TestBoard_WritePNG(t *testing.T) {
+	b, err := ParseBoard(strings.NewReader("AABB\nAABB\n....\n....\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := b.WritePNG(&buf, ImageOptions{Labels: true, Grid: true}); err != nil {
+		t.Fatalf("WritePNG() error: %v", err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error: %v", err)
+	}
+	want := b.Image(ImageOptions{Labels: true, Grid: true})
+	if decoded.Bounds() != want.Bounds() {
+		t.Fatalf("decoded bounds %v, want %v", decoded.Bounds(), want.Bounds())
+	}
+	for y := 0; y < want.Bounds().Dy(); y++ {
+		for x := 0; x < want.Bounds().Dx(); x++ {
+			if r1, g1, b1, a1 := decoded.At(x, y).RGBA(); [4]uint32{r1, g1, b1, a1} != rgbaOf(want.At(x, y)) {
+				t.Fatalf("pixel (%d, %d) differs after decoding", x, y)
+			}
+		}
+	}
+}
+
+// rgbaOf returns the premultiplied components of c.
+func rgbaOf(c color.Color) [4]uint32 {
+	r, g, b, a := c.RGBA()
+	return [4]uint32{r, g, b, a}
+}
+
+// TestGlyphs checks that every label has a well-formed 5x7 glyph.
+func TestGlyphs(t *testing.T) {
+	for label := byte('A'); label <= 'Z'; label++ {
+		rows := strings.Fields(glyphs[label])
+		if len(rows) != 7 {
+			t.Errorf("glyph %c has %d rows, want 7", label, len(rows))
+		}
+		for _, row := range rows {
+			if len(row) != 5 || strings.Trim(row, ".#") != "" {
+				t.Errorf("glyph %c has bad row %q", label, row)
+			}
+		}
+	}
+}
+
+// TestCentralCell checks label placement inside bent pieces.
+func TestCentralCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []Point
+		want  Point
+	}{
+		{"I", []Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}}, Point{0, 1}},
+		{"L", []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}, Point{1, 0}},
+		{"T", []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}, Point{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := centralCell(tt.cells); got != tt.want {
+				t.Errorf("centralCell() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
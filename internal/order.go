@@ -0,0 +1,128 @@
+// Package internal implements piece ordering heuristics for the backtracker.
+package internal
+
+import "sort"
+
+// PieceOrder selects which piece the default backtracker places next. Labels
+// always follow input order; only the search order changes.
+type PieceOrder int
+
+const (
+	InputOrder       PieceOrder = iota // Pieces in input order (the default)
+	ConstrainedFirst                   // I, then S/Z, then T/L/J, then O pieces; input order within a group
+	FewestPlacements                   // At each node, the unplaced piece with the fewest legal placements
+	LargestBox                         // Largest bounding box area first, longer side breaking ties
+)
+
+func (o PieceOrder) String() string {
+	switch o { // Name used by --order
+	case ConstrainedFirst:
+		return "constrained"
+	case FewestPlacements:
+		return "dynamic"
+	case LargestBox:
+		return "bbox"
+	}
+	return "input"
+}
+
+// familyRank orders tetromino families from hardest to easiest to fit: long
+// and skewed pieces leave awkward gaps, the square rarely does.
+var familyRank = map[byte]int{'I': 0, 'S': 1, 'Z': 1, 'T': 2, 'L': 2, 'J': 2, 'O': 3}
+
+// orderPieces returns the pieces in the static order selected by order.
+// FewestPlacements is decided per node and leaves input order unchanged.
+func orderPieces(pieces []*Tetromino, order PieceOrder) []*Tetromino {
+	ordered := append([]*Tetromino{}, pieces...) // Copy: the caller's slice keeps input order
+	switch order {                               // Static orders only
+	case ConstrainedFirst: // By family rank
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return familyRank[ShapeFamily(ordered[i].Coords)] < familyRank[ShapeFamily(ordered[j].Coords)]
+		})
+	case LargestBox: // By bounding box
+		sort.SliceStable(ordered, func(i, j int) bool {
+			hi, wi := extent(ordered[i].Coords)
+			hj, wj := extent(ordered[j].Coords)
+			if hi*wi != hj*wj { // Different areas
+				return hi*wi > hj*wj // Larger area first
+			}
+			return max(hi, wi) > max(hj, wj) // Longer side first
+		})
+	}
+	return ordered
+}
+
+// solveDynamic is solve with the piece chosen per node: the unplaced piece
+// with the fewest legal placements on the current board, earliest input
+// position breaking ties. A piece with no placement left fails the node at once.
+func (s *search) solveDynamic(b *Board, used []bool, placed int) bool {
+	select {
+	case <-s.ctx.Done(): // Check for cancellation periodically
+		return false
+	default: // Continue if not cancelled
+	}
+	s.publish(b, placed)
+
+	if placed == len(s.pieces) { // All pieces placed successfully
+		return true
+	}
+
+	best, fewest := -1, 0        // No piece chosen yet
+	for i, p := range s.pieces { // Every unplaced piece
+		if used[i] { // Already on the board
+			continue
+		}
+		limit := b.Size * b.Size // Counting past the current best cannot change the choice
+		if best >= 0 {           // Some piece already chosen
+			limit = fewest
+		}
+		n := countPlacements(b, p, limit) // Legal placements left for p
+		if n == 0 {                       // Dead end: this piece no longer fits anywhere
+			return false
+		}
+		if best < 0 || n < fewest { // Fewest placements so far
+			best, fewest = i, n
+		}
+	}
+
+	piece := s.pieces[best]             // Piece to place at this node
+	used[best] = true                   // Mark it placed for the subtree
+	k := 0                              // Branch being tried, of fewest, for the progress estimate
+	for row := 0; row < b.Size; row++ { // Try each row position
+		for col := 0; col < b.Size; col++ { // Try each column position
+			if b.CanPlace(piece, row, col) { // Check if piece can be placed here
+				s.enter(placed, k, fewest) // Progress estimate for this branch
+				k++
+				newBoard := b.Copy()            // Create board copy for backtracking
+				newBoard.Place(piece, row, col) // Place piece on the copy
+				s.nodes++                       // Count the node
+
+				if s.solveDynamic(newBoard, used, placed+1) {
+					*b = *newBoard // Propagate successful solution back up the call stack
+					return true
+				}
+				if s.ctx.Err() != nil { // Cancelled inside the subtree
+					return false
+				}
+			}
+		}
+	}
+	used[best] = false // Backtrack: free the piece again
+	return false
+}
+
+// countPlacements counts the legal placements of p on b, stopping at limit.
+func countPlacements(b *Board, p *Tetromino, limit int) int {
+	n := 0
+	for row := 0; row < b.Size; row++ { // Try each row position
+		for col := 0; col < b.Size; col++ { // Try each column position
+			if b.CanPlace(p, row, col) { // Legal placement
+				n++
+				if n >= limit { // Enough to lose to the current best
+					return n
+				}
+			}
+		}
+	}
+	return n
+}
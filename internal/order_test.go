@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOrderPieces(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}}, // O
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}}, // T, 2x3
+		{Label: 'C', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}}, // I, 4x1
+		{Label: 'D', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}}, // S, 2x3
+		{Label: 'E', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}}, // L, 3x2
+		{Label: 'F', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}}}, // I, 1x4
+	}
+
+	tests := []struct {
+		order PieceOrder
+		want  string
+	}{
+		{InputOrder, "ABCDEF"},
+		{ConstrainedFirst, "CFDBEA"},
+		{FewestPlacements, "ABCDEF"}, // Decided per node, not up front
+		{LargestBox, "BDECFA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			var got []byte
+			for _, p := range orderPieces(pieces, tt.order) {
+				got = append(got, p.Label)
+			}
+			if string(got) != tt.want {
+				t.Errorf("orderPieces(%v) = %s, want %s", tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSolveWith_Orders checks that every ordering finds a valid board of the minimal size.
+func TestSolveWith_Orders(t *testing.T) {
+	inputs := []struct {
+		name   string
+		pieces []*Tetromino
+	}{
+		{"L and J", logPieces},
+		{"hard example minus one", parsePiecesFromString(t, hardExample)[:11]},
+	}
+
+	for _, order := range []PieceOrder{InputOrder, ConstrainedFirst, FewestPlacements, LargestBox} {
+		for _, in := range inputs {
+			t.Run(order.String()+"/"+in.name, func(t *testing.T) {
+				ctx := context.Background()
+				want := Solve(ctx, in.pieces)
+
+				got := SolveWith(ctx, in.pieces, Options{Order: order})
+				if got.Board == nil {
+					t.Fatalf("SolveWith(%v) = %+v, want a board", order, got.Sizes)
+				}
+				if got.Board.Size != want.Board.Size {
+					t.Errorf("SolveWith(%v) size = %d, want %d", order, got.Board.Size, want.Board.Size)
+				}
+				if err := Verify(in.pieces, got.Board); err != nil {
+					t.Errorf("SolveWith(%v) board invalid: %v\n%s", order, err, got.Board)
+				}
+			})
+		}
+	}
+}
+
+// TestSolveWith_OrderHardExample solves the hard example, which input order
+// cannot do quickly, with the bounding box order.
+func TestSolveWith_OrderHardExample(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping slow test in short mode")
+	}
+	pieces := parsePiecesFromString(t, hardExample)
+
+	result := SolveWith(context.Background(), pieces, Options{Order: LargestBox})
+	if result.Board == nil || result.Board.Size != 7 {
+		t.Fatalf("SolveWith(LargestBox) = %+v, want 7x7", result.Sizes)
+	}
+	if err := Verify(pieces, result.Board); err != nil {
+		t.Errorf("board invalid: %v\n%s", err, result.Board)
+	}
+}
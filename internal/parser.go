@@ -4,6 +4,7 @@ package internal
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -30,19 +31,35 @@ func ParseFile(filename string) ([]*Tetromino, error) {
 	}
 	defer file.Close() // Ensure file is closed on exit
 
-	var lines []string                // Collect all lines from file
-	scanner := bufio.NewScanner(file) // bufio.Scanner splits on \n but leaves \r on Windows files
-	for scanner.Scan() {              // Read file line by line
-		line := scanner.Text()                // Get line without trailing \n
-		line = strings.TrimSuffix(line, "\r") // Handle Windows CRLF line endings
-		lines = append(lines, line)           // Add line to collection
+	lines, err := readLines(file) // Collect all lines from file
+	if err != nil {
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil { // Check for read errors
+	return parseLines(lines) // Process collected lines
+}
+
+// readLines reads all lines, stripping line endings. Read errors are
+// reported as a ParseError, like the puzzle's own mistakes.
+func readLines(r io.Reader) ([]string, error) {
+	lines, err := scanLines(r) // Lines, or the scanner's error
+	if err != nil {            // Check for read errors
 		return nil, &ParseError{Message: fmt.Sprintf("error reading file: %s", err.Error())}
 	}
+	return lines, nil // Lines without endings
+}
 
-	return parseLines(lines) // Process collected lines
+// scanLines reads all lines, stripping line endings, and returns read errors as they are.
+func scanLines(r io.Reader) ([]string, error) {
+	var lines []string             // Collect all lines
+	scanner := bufio.NewScanner(r) // bufio.Scanner splits on \n but leaves \r on Windows files
+	for scanner.Scan() {           // Read line by line
+		line := scanner.Text()                // Get line without trailing \n
+		line = strings.TrimSuffix(line, "\r") // Handle Windows CRLF line endings
+		lines = append(lines, line)           // Add line to collection
+	}
+
+	return lines, scanner.Err() // Nil unless reading failed
 }
 
 // parseLines processes the file content and extracts tetrominoes.
@@ -132,3 +149,48 @@ func parseLines(lines []string) ([]*Tetromino, error) {
 
 	return tetrominoes, nil
 }
+
+// ParseBoard reads a solution grid in the format produced by Board.String():
+// one row per line, '.' for empty cells and 'A'-'Z' for pieces.
+// The grid must be square and every label must form a valid tetromino;
+// use Board.Placements to recover where each piece sits. Mistakes in the
+// grid are reported as *ParseError; failures to read r are not.
+func ParseBoard(r io.Reader) (*Board, error) {
+	lines, err := scanLines(r) // Lines, or the reader's error
+	if err != nil {            // Not a ParseError: the grid may be fine, it just could not be read
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" { // Remove trailing empty lines
+		lines = lines[:len(lines)-1] // Trim last element
+	}
+
+	if len(lines) == 0 { // No grid at all
+		return nil, &ParseError{Message: "empty board"}
+	}
+
+	b := NewBoard(len(lines))      // Size is fixed by the row count
+	for row, line := range lines { // One row per line
+		if len(line) != b.Size { // Board must be square
+			return nil, &ParseError{Message: fmt.Sprintf("row %d has %d characters (expected %d)", row+1, len(line), b.Size)}
+		}
+		for col := 0; col < len(line); col++ { // Validate each character
+			ch := line[col]                          // Byte at this column
+			if ch != '.' && (ch < 'A' || ch > 'Z') { // Only '.' and labels allowed
+				return nil, &ParseError{Message: fmt.Sprintf("invalid character '%c' at row %d, column %d", ch, row+1, col+1)}
+			}
+			b.Grid[row][col] = ch // Copy the label onto the board
+		}
+	}
+
+	for _, p := range b.Placements() { // Every label must be one real tetromino
+		if n := len(p.Piece.Coords); n != 4 { // Wrong number of cells
+			return nil, &ParseError{Message: fmt.Sprintf("piece %c has %d cells (expected 4)", p.Piece.Label, n)}
+		}
+		if !MatchShape(p.Piece.Coords) { // Not one of the 19 shapes
+			return nil, &ParseError{Message: fmt.Sprintf("piece %c is not a valid tetromino", p.Piece.Label)}
+		}
+	}
+
+	return b, nil // Square grid of valid pieces
+}
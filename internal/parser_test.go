@@ -1,8 +1,12 @@
 package internal
 
 import (
+	"context"
+	"errors"
 	"os"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestParseError(t *testing.T) {
@@ -418,3 +422,83 @@ func createTempFile(t *testing.T, content string) string {
 	f.Close()
 	return f.Name()
 }
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"solved board", "ABBBB\nACCC.\nA..C.\nADD..\nDD...\n", "ABBBB\nACCC.\nA..C.\nADD..\nDD...\n", false},
+		{"CRLF and trailing blank lines", "AA\r\nAA\r\n\r\n\n", "AA\nAA\n", false},
+		{"empty board cells only", "..\n..\n", "..\n..\n", false},
+		{"empty input", "", "", true},
+		{"not square", "AA.\nAA.\n", "", true},
+		{"ragged rows", "AA..\nAA.\n....\n....\n", "", true},
+		{"lowercase label", "aa\naa\n", "", true},
+		{"hash character", "##\n##\n", "", true},
+		{"too few cells", "AAA\n...\n...\n", "", true},
+		{"too many cells", "AAA\nAA.\n...\n", "", true},
+		{"invalid shape", "A..\n.A.\nAA.\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseBoard(strings.NewReader(tt.input))
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseBoard() expected error, got board:\n%s", b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBoard() unexpected error: %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("ParseBoard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseBoard_ReadError checks that a failed read is not mistaken for a bad grid.
+func TestParseBoard_ReadError(t *testing.T) {
+	readErr := errors.New("disk failure")
+	_, err := ParseBoard(iotest.ErrReader(readErr))
+	var perr *ParseError
+	if !errors.Is(err, readErr) || errors.As(err, &perr) {
+		t.Errorf("ParseBoard() error = %v (%T), want the read error, not a ParseError", err, err)
+	}
+}
+
+// TestParseBoard_InvalidCharacterPosition checks that the error names the offending cell.
+func TestParseBoard_InvalidCharacterPosition(t *testing.T) {
+	_, err := ParseBoard(strings.NewReader("A..\nAA#\nA..\n"))
+	want := "invalid character '#' at row 2, column 3"
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseBoard() error = %v, want %q", err, want)
+	}
+}
+
+// TestParseBoard_RoundTrip checks that ParseBoard inverts Board.String for solver output.
+func TestParseBoard_RoundTrip(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+		{Label: 'C', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+	}
+	result := Solve(context.Background(), pieces)
+
+	b, err := ParseBoard(strings.NewReader(result.Board.String()))
+	if err != nil {
+		t.Fatalf("ParseBoard() error = %v", err)
+	}
+	if b.String() != result.Board.String() {
+		t.Errorf("ParseBoard() = %q, want %q", b.String(), result.Board.String())
+	}
+	if err := Verify(pieces, b); err != nil {
+		t.Errorf("Verify(parsed) error = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Package internal races several solver configurations against each other.
+package internal
+
+import (
+	"context"
+	"fmt"
+)
+
+// Strategy is one solver configuration raced by SolvePortfolio.
+type Strategy struct {
+	Name    string
+	Options Options
+}
+
+// DefaultPortfolio returns the strategies raced by default: every piece order,
+// the SAT backend and randomized restarts with seeds 1 to seeds. They tend to
+// win on different inputs: input order on loose fits, bbox and dynamic orders
+// and SAT on dense ones, restarts where a fixed order is unlucky. There is no
+// dancing-links (DLX) exact-cover backend to include; SAT takes that role.
+func DefaultPortfolio(seeds int) []Strategy {
+	var strategies []Strategy                                                                        // Collect the strategies in race order
+	for _, order := range []PieceOrder{InputOrder, ConstrainedFirst, FewestPlacements, LargestBox} { // Every piece order
+		strategies = append(strategies, Strategy{Name: "order=" + order.String(), Options: Options{Order: order}}) // Default backtracker in that order
+	}
+	strategies = append(strategies, Strategy{Name: "backend=sat", Options: Options{Backend: SAT}}) // CDCL backend
+	for seed := 1; seed <= seeds; seed++ {                                                         // One restart strategy per seed
+		strategies = append(strategies, Strategy{
+			Name:    fmt.Sprintf("restarts seed=%d", seed),
+			Options: Options{Restarts: true, Seed: int64(seed)},
+		})
+	}
+	return strategies
+}
+
+// portfolioEntry is one strategy's finished result.
+type portfolioEntry struct {
+	name   string
+	result *Result
+}
+
+// SolvePortfolio runs every strategy concurrently and returns the first result
+// that is proven minimal, cancelling the others; Result.Strategy names the
+// winner. It does not wait for the losers, which stop in the background when
+// they next check their context. When two finish together either may win, so
+// the board can vary between runs (use Canonical in every strategy for a
+// stable answer). If ctx ends first, the result has Timeout set and no board.
+func SolvePortfolio(ctx context.Context, pieces []*Tetromino, strategies []Strategy) *Result {
+	ctx, cancel := context.WithCancel(ctx) // Cancels the losers once a winner is in
+	defer cancel()
+
+	done := make(chan portfolioEntry, len(strategies)) // Buffered: losers never block after the winner returns
+	for _, st := range strategies {                    // One search per strategy
+		go func(st Strategy) { // Race on its own goroutine
+			done <- portfolioEntry{st.Name, SolveWith(ctx, pieces, st.Options)} // Report the result, whatever it is
+		}(st)
+	}
+
+	for range strategies { // Wait for results as they come in
+		e := <-done                                                            // Next finisher
+		if !e.result.Timeout && e.result.Err == nil && e.result.Board != nil { // Finished: the board is minimal
+			e.result.Strategy = e.name
+			return e.result // Deferred cancel stops the losers
+		}
+	}
+	return &Result{Timeout: true} // Every strategy was cancelled
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestSolvePortfolio checks that the winner is minimal, valid and named.
+func TestSolvePortfolio(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+	result := SolvePortfolio(context.Background(), pieces, DefaultPortfolio(2))
+
+	if result.Timeout || result.Board == nil {
+		t.Fatalf("SolvePortfolio() = %+v, want a board", result)
+	}
+	if result.Board.Size != 7 {
+		t.Errorf("SolvePortfolio() size = %d, want 7", result.Board.Size)
+	}
+	if err := Verify(pieces, result.Board); err != nil {
+		t.Errorf("SolvePortfolio() board invalid: %v", err)
+	}
+	known := false
+	for _, st := range DefaultPortfolio(2) {
+		known = known || st.Name == result.Strategy
+	}
+	if !known {
+		t.Errorf("SolvePortfolio() strategy = %q, want one of the portfolio", result.Strategy)
+	}
+}
+
+// TestSolvePortfolio_Single checks that a one-strategy portfolio matches SolveWith.
+func TestSolvePortfolio_Single(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:6]
+	opts := Options{Order: LargestBox}
+	want := SolveWith(context.Background(), pieces, opts)
+	got := SolvePortfolio(context.Background(), pieces, []Strategy{{Name: "bbox", Options: opts}})
+
+	if got.Strategy != "bbox" {
+		t.Errorf("Strategy = %q, want %q", got.Strategy, "bbox")
+	}
+	if got.Board.String() != want.Board.String() {
+		t.Errorf("SolvePortfolio() board:\n%s\nwant:\n%s", got.Board, want.Board)
+	}
+}
+
+// TestSolvePortfolio_Cancelled checks that a cancelled portfolio reports a timeout.
+func TestSolvePortfolio_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := SolvePortfolio(ctx, parsePiecesFromString(t, hardExample), DefaultPortfolio(1))
+
+	if !result.Timeout || result.Board != nil || result.Strategy != "" {
+		t.Errorf("SolvePortfolio(cancelled) = %+v, want a bare timeout", result)
+	}
+}
+
+// TestDefaultPortfolio checks that strategy names are distinct.
+func TestDefaultPortfolio(t *testing.T) {
+	seen := map[string]bool{}
+	for _, st := range DefaultPortfolio(3) {
+		if seen[st.Name] || strings.TrimSpace(st.Name) == "" {
+			t.Errorf("duplicate or empty strategy name %q", st.Name)
+		}
+		seen[st.Name] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("DefaultPortfolio(3) has %d strategies, want 8", len(seen))
+	}
+}
@@ -0,0 +1,139 @@
+// Package internal publishes the state of a running search for display.
+package internal
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// progressEvery is how many placements pass between progress updates.
+	progressEvery = 1024
+	// estimateDepth is how many levels of the search tree the progress
+	// estimate looks at. Deeper levels would refine it by less than
+	// 1/50^estimateDepth on typical boards.
+	estimateDepth = 4
+)
+
+// Progress receives snapshots of a running search (see Options.Progress). The
+// search publishes each snapshot atomically; any goroutine may read them.
+type Progress struct {
+	snap atomic.Pointer[ProgressSnapshot]
+	base int64 // Placements tried at sizes finished before the current one; search goroutine only
+}
+
+// ProgressSnapshot describes a search at one moment.
+type ProgressSnapshot struct {
+	Size     int       // Board size being searched (0 before the first search)
+	Depth    int       // Pieces on Board
+	MaxDepth int       // Most pieces placed at once at this size
+	Nodes    int64     // Placements tried at this size
+	Total    int64     // Placements tried at all sizes so far
+	Board    *Board    // Partial board, never modified once published; nil for the SAT backend
+	Explored float64   // Estimated fraction of this size's search tree done, 0 to 1; negative if unknown
+	Started  time.Time // When the search of this size began
+}
+
+// Snapshot returns the latest state of the search; empty for a nil Progress.
+func (p *Progress) Snapshot() ProgressSnapshot {
+	if p == nil { // No progress asked for
+		return ProgressSnapshot{}
+	}
+	if snap := p.snap.Load(); snap != nil { // Published at least once
+		return *snap
+	}
+	return ProgressSnapshot{}
+}
+
+// ETA estimates how long exhausting the current size will take, from the time
+// spent on it so far and the fraction explored. The search finishes sooner if
+// it finds a board. ok is false while there is no estimate.
+func (s ProgressSnapshot) ETA() (eta time.Duration, ok bool) {
+	if s.Size == 0 || s.Explored <= 0 { // Not searching yet, or no estimate
+		return 0, false
+	}
+	elapsed := time.Since(s.Started)                                             // Time spent on this size
+	return time.Duration(float64(elapsed) * (1 - s.Explored) / s.Explored), true // Scale by the part still to do
+}
+
+// startSize records that the search moved on to a new board size; estimated
+// tells whether it will report Explored.
+func (p *Progress) startSize(size int, board *Board, estimated bool) {
+	if last := p.snap.Load(); last != nil { // Previous size finished
+		p.base += last.Nodes // Carry its placements into the total
+	}
+	snap := &ProgressSnapshot{Size: size, Total: p.base, Board: board, Explored: -1, Started: time.Now()} // Fresh snapshot for the new size
+	if estimated {                                                                                        // Search keeps an estimate
+		snap.Explored = 0
+	}
+	p.snap.Store(snap) // Publish atomically
+}
+
+// update records the search's current node; b may be nil for the SAT backend.
+func (p *Progress) update(b *Board, depth, maxDepth int, nodes int64, explored float64) {
+	snap := *p.snap.Load() // Set by startSize
+	if b != nil {          // Backtracker: board to show
+		b = b.Copy() // The search keeps working on b
+	}
+	snap.Board, snap.Depth, snap.MaxDepth = b, depth, maxDepth            // Position of the search
+	snap.Nodes, snap.Total, snap.Explored = nodes, p.base+nodes, explored // Counters and estimate
+	p.snap.Store(&snap)                                                   // Publish atomically
+}
+
+// branch is a node's place among its siblings: the i-th of n, counting from 0.
+type branch struct{ i, n int }
+
+// publish reports the node on b at the given depth to s.progress, if one is
+// set, every progressEvery placements. Called on entering a node.
+func (s *search) publish(b *Board, depth int) {
+	if s.progress == nil { // No one is watching
+		return
+	}
+	s.maxDepth = max(s.maxDepth, depth) // Deepest point so far
+	if s.nodes < s.nextProgress {       // Not due yet
+		return
+	}
+	s.nextProgress = s.nodes + progressEvery                       // Schedule the next update
+	s.progress.update(b, depth, s.maxDepth, s.nodes, s.explored()) // Publish a copy of this node
+}
+
+// estimating reports whether the node at depth in the search tree takes part
+// in the progress estimate, and so must count its branches for enter.
+func (s *search) estimating(depth int) bool {
+	return depth < len(s.est)
+}
+
+// enter records that the search descends into the i-th of the n branches of
+// the node at depth. Ignored below the levels the estimate looks at.
+func (s *search) enter(depth, i, n int) {
+	if depth < len(s.est) { // Within the estimated levels
+		s.est[depth] = branch{i, n} // Remember this branch
+		s.level = depth + 1         // Levels below are stale
+	}
+}
+
+// explored estimates the fraction of the tree searched so far, assuming every
+// branch of a node takes as long: each finished branch at depth d counts for
+// 1/n of its parent's share. -1 if the search keeps no estimate.
+func (s *search) explored() float64 {
+	if s.est == nil {
+		return -1
+	}
+	f, share := 0.0, 1.0                 // Fraction done and share of the current node
+	for _, br := range s.est[:s.level] { // Path to the current node
+		share /= float64(br.n)     // Each branch's share of its parent
+		f += float64(br.i) * share // Earlier siblings are finished
+	}
+	return f
+}
+
+// placementsBefore counts the cells before end, in row-major order, where p fits on b.
+func placementsBefore(b *Board, p *Tetromino, end int) int {
+	n := 0
+	for cell := 0; cell < end; cell++ { // Every cell before end
+		if b.CanPlace(p, cell/b.Size, cell%b.Size) { // Legal placement
+			n++
+		}
+	}
+	return n
+}
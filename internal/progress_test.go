@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSolveWith_Progress checks the final snapshot of each search against its result.
+func TestSolveWith_Progress(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:11]
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"input", Options{}},
+		{"dynamic", Options{Order: FewestPlacements}},
+		{"canonical", Options{Canonical: true}},
+		{"restarts", Options{Restarts: true, Seed: 1}},
+		{"sat", Options{Backend: SAT}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Progress{}
+			tt.opts.Progress = p
+			res := SolveWith(context.Background(), pieces, tt.opts)
+			if res.Board == nil {
+				t.Fatalf("SolveWith() = %+v, want a board", res.Sizes)
+			}
+
+			snap := p.Snapshot()
+			var total int64
+			for _, r := range res.Sizes {
+				total += r.Nodes
+			}
+			last := res.Sizes[len(res.Sizes)-1]
+			if snap.Size != res.Board.Size || snap.Nodes != last.Nodes || snap.Total != total {
+				t.Errorf("Snapshot() size %d, nodes %d, total %d; want %d, %d, %d",
+					snap.Size, snap.Nodes, snap.Total, res.Board.Size, last.Nodes, total)
+			}
+			if estimated := !tt.opts.Restarts && tt.opts.Backend != SAT; estimated != (snap.Explored >= 0) || snap.Explored > 1 {
+				t.Errorf("Snapshot().Explored = %v, want an estimate in [0, 1]: %v", snap.Explored, estimated)
+			}
+			if tt.opts.Backend == SAT {
+				if snap.Board != nil {
+					t.Errorf("Snapshot().Board = %v, want nil for SAT", snap.Board)
+				}
+				return
+			}
+			if snap.Board == nil || snap.Board.String() != res.Board.String() {
+				t.Errorf("Snapshot().Board = %v, want the solution\n%s", snap.Board, res.Board)
+			}
+			if snap.Depth != len(pieces) || snap.MaxDepth != len(pieces) {
+				t.Errorf("Snapshot() depth %d (max %d), want %d", snap.Depth, snap.MaxDepth, len(pieces))
+			}
+		})
+	}
+}
+
+// TestProgress_Concurrent reads snapshots while a search runs; run with -race.
+func TestProgress_Concurrent(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:11]
+	p := &Progress{}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			snap := p.Snapshot()
+			if snap.Board == nil {
+				continue
+			}
+			if snap.Board.Size != snap.Size || snap.Board.Size*snap.Board.Size-snap.Board.CountEmpty() != 4*snap.Depth {
+				t.Errorf("Snapshot() board of size %d with %d empty cells, want size %d with %d pieces",
+					snap.Board.Size, snap.Board.CountEmpty(), snap.Size, snap.Depth)
+				return
+			}
+		}
+	}()
+
+	SolveWith(context.Background(), pieces, Options{Progress: p})
+	close(done)
+	wg.Wait()
+}
+
+// TestSolveSize_ProgressExhausted checks that an exhausted size ends fully explored.
+func TestSolveSize_ProgressExhausted(t *testing.T) {
+	for _, opts := range []Options{{}, {Order: FewestPlacements}, {Canonical: true}} {
+		p := &Progress{}
+		opts.Progress = p
+		res := SolveSize(context.Background(), logPieces, 3, opts)
+		if res.Sizes[0].Outcome != Exhausted {
+			t.Fatalf("SolveSize(3, %v) = %v, want exhausted", opts, res.Sizes[0].Outcome)
+		}
+		if got := p.Snapshot().Explored; got != 1 {
+			t.Errorf("SolveSize(3, %v) explored %v, want 1", opts, got)
+		}
+	}
+}
+
+// TestSearch_Explored checks the estimate along a walk down and across the tree.
+func TestSearch_Explored(t *testing.T) {
+	s := &search{est: make([]branch, 2)}
+	steps := []struct {
+		depth, i, n int
+		want        float64
+	}{
+		{0, 0, 4, 0},
+		{1, 0, 5, 0},
+		{1, 2, 5, 0.1},          // 2/5 of the first quarter
+		{1, 4, 5, 0.2},          // Last branch of the first quarter
+		{0, 1, 4, 0.25},         // First quarter done
+		{2, 3, 10, 0.25},        // Below the estimated levels: no change
+		{1, 1, 2, 0.25 + 0.125}, // Half of the second quarter
+		{0, 3, 4, 0.75},
+	}
+	for _, st := range steps {
+		s.enter(st.depth, st.i, st.n)
+		if got := s.explored(); got != st.want {
+			t.Errorf("after enter(%d, %d, %d) explored() = %v, want %v", st.depth, st.i, st.n, got, st.want)
+		}
+	}
+
+	if got := (&search{}).explored(); got != -1 {
+		t.Errorf("explored() without an estimate = %v, want -1", got)
+	}
+}
+
+// TestProgressSnapshot_ETA checks the remaining time is scaled from the time spent.
+func TestProgressSnapshot_ETA(t *testing.T) {
+	started := time.Now().Add(-3 * time.Second)
+	tests := []struct {
+		snap   ProgressSnapshot
+		want   time.Duration
+		wantOK bool
+	}{
+		{ProgressSnapshot{Size: 5, Explored: 0.25, Started: started}, 9 * time.Second, true},
+		{ProgressSnapshot{Size: 5, Explored: 1, Started: started}, 0, true},
+		{ProgressSnapshot{Size: 5, Explored: 0, Started: started}, 0, false},  // Nothing done yet
+		{ProgressSnapshot{Size: 5, Explored: -1, Started: started}, 0, false}, // No estimate
+		{ProgressSnapshot{}, 0, false},                                        // Not started
+	}
+	for _, tt := range tests {
+		got, ok := tt.snap.ETA()
+		if ok != tt.wantOK || got < tt.want || got > tt.want+tt.want/10+time.Second/10 {
+			t.Errorf("ETA() of %v explored = %v, %v; want about %v, %v", tt.snap.Explored, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
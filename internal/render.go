@@ -0,0 +1,193 @@
+// Package internal renders boards for display.
+package internal
+
+import "strings"
+
+// familyColors holds the ANSI background colours of each tetromino family,
+// after the classic Tetris palette: a normal and a brighter shade, so that two
+// touching pieces of the same family stay apart.
+var familyColors = map[byte][2]string{
+	'I': {"46", "106"},            // Cyan
+	'O': {"43", "103"},            // Yellow
+	'T': {"45", "105"},            // Magenta
+	'S': {"42", "102"},            // Green
+	'Z': {"41", "101"},            // Red
+	'J': {"44", "104"},            // Blue
+	'L': {"48;5;208", "48;5;214"}, // Orange (256-colour; no 16-colour orange exists)
+	0:   {"47", "107"},            // Not a tetromino: grey
+}
+
+// ColorString renders the board like String, with each piece's letters in
+// black on the colour of its shape family. Empty cells stay plain.
+func (b *Board) ColorString() string {
+	family, shade := b.pieceShades() // Colour choice per label
+
+	var sb strings.Builder       // Output accumulator
+	for _, row := range b.Grid { // Iterate through each row
+		for i := 0; i < len(row); { // Advance a run at a time
+			j := i + 1                             // Colour runs of one label with a single escape sequence
+			for j < len(row) && row[j] == row[i] { // Extend the run
+				j++
+			}
+			if row[i] == '.' { // Empty cells stay plain
+				sb.Write(row[i:j])
+			} else {
+				sb.WriteString("\x1b[30;" + familyColors[family[row[i]]][shade[row[i]]] + "m") // Black letters on the family colour
+				sb.Write(row[i:j])
+				sb.WriteString("\x1b[0m") // Reset after the run
+			}
+			i = j // Next run
+		}
+		sb.WriteByte('\n') // End of row
+	}
+	return sb.String()
+}
+
+// pieceShades returns the tetromino family of each label and its shade: 0 for
+// the normal colour, 1 for the brighter one. Each group of touching pieces of
+// one family is shaded outward from its lowest label, every piece taking a
+// shade none of its shaded neighbours has, so neighbours of one family stay
+// apart. Only a ring of an odd number of such pieces can defeat two shades.
+func (b *Board) pieceShades() (map[byte]byte, map[byte]int) {
+	family := make(map[byte]byte)  // Label -> tetromino family
+	placements := b.Placements()   // Label order
+	for _, p := range placements { // Every piece on the board
+		family[p.Piece.Label] = ShapeFamily(p.Piece.Coords) // Family of the piece's shape
+	}
+
+	touching := make(map[byte][]byte) // Label -> touching labels of its family
+	link := func(x, y byte) {         // Record one edge between two pieces
+		if x == '.' || y == '.' || x == y || family[x] != family[y] { // Empty, same piece, or different families
+			return
+		}
+		for _, n := range touching[x] {
+			if n == y { // Already linked
+				return
+			}
+		}
+		touching[x] = append(touching[x], y) // Both directions
+		touching[y] = append(touching[y], x)
+	}
+	for r := 0; r < b.Size; r++ { // Iterate through each row
+		for c := 0; c < b.Size; c++ { // Iterate through each cell
+			if r+1 < b.Size { // Cell below
+				link(b.Grid[r][c], b.Grid[r+1][c])
+			}
+			if c+1 < b.Size { // Cell to the right
+				link(b.Grid[r][c], b.Grid[r][c+1])
+			}
+		}
+	}
+
+	shade := make(map[byte]int)    // Label -> 0 (normal) or 1 (bright); absent until shaded
+	for _, p := range placements { // Every piece, lowest label first
+		if _, ok := shade[p.Piece.Label]; ok { // Reached from a lower label
+			continue
+		}
+		queue := []byte{p.Piece.Label} // Breadth-first from this piece
+		for len(queue) > 0 {           // Until the group is shaded
+			label := queue[0] // Next piece in the group
+			queue = queue[1:]
+			if _, ok := shade[label]; ok { // Queued twice
+				continue
+			}
+			var used [2]bool                    // Shades taken by shaded neighbours
+			for _, n := range touching[label] { // Every touching piece of its family
+				if s, ok := shade[n]; ok { // Already shaded
+					used[s] = true
+				} else {
+					queue = append(queue, n) // Shade it later
+				}
+			}
+			if used[0] && !used[1] { // Only the normal shade is taken
+				shade[label] = 1
+			} else {
+				shade[label] = 0
+			}
+		}
+	}
+	return family, shade
+}
+
+// at returns the label at (r, c), or 0 outside the board.
+func (b *Board) at(r, c int) byte {
+	if r < 0 || r >= b.Size || c < 0 || c >= b.Size { // Off the board
+		return 0
+	}
+	return b.Grid[r][c]
+}
+
+// boxCorners maps the lines meeting at a grid corner (up=1, right=2, down=4,
+// left=8) to the box-drawing character joining them.
+var boxCorners = []rune(" ╵╶└╷│┌├╴┘─┴┐┤┬┼")
+
+// BoxString renders the board with box-drawing characters: each piece is
+// outlined and keeps its letter, empty cells are shaded, and the board has a
+// frame. A border is drawn wherever the two cells it separates hold different
+// labels, so adjacent empty cells merge into one shaded area. Cells are three
+// characters wide to look roughly square in a terminal.
+func (b *Board) BoxString() string {
+	if b.Size == 0 {
+		return ""
+	}
+	region := b.at                                                      // Label at a cell, 0 outside
+	across := func(r, c int) bool { return b.at(r-1, c) != b.at(r, c) } // Border above cell (r, c)
+	down := func(r, c int) bool { return b.at(r, c-1) != b.at(r, c) }   // Border left of cell (r, c)
+
+	var sb strings.Builder
+	for r := 0; r <= b.Size; r++ { // Grid lines, including the bottom frame
+		for c := 0; c <= b.Size; c++ { // Corners and horizontal borders
+			arms := 0         // Lines meeting at this corner
+			if down(r-1, c) { // Border above, on the left
+				arms |= 1
+			}
+			if across(r, c) { // Border to the right
+				arms |= 2
+			}
+			if down(r, c) { // Border below
+				arms |= 4
+			}
+			if across(r, c-1) { // Border to the left
+				arms |= 8
+			}
+			if arms == 0 && region(r, c) == '.' { // Inside an empty area
+				sb.WriteString("░")
+			} else {
+				sb.WriteRune(boxCorners[arms])
+			}
+			switch { // Span to the next corner
+			case c == b.Size: // Right frame: no span
+			case across(r, c): // Border
+				sb.WriteString("───")
+			case region(r, c) == '.': // Empty area continues
+				sb.WriteString("░░░")
+			default: // Inside a piece
+				sb.WriteString("   ")
+			}
+		}
+		sb.WriteByte('\n')
+		if r == b.Size { // Bottom frame done
+			break
+		}
+
+		for c := 0; c <= b.Size; c++ { // Vertical borders and cell contents
+			switch { // Left edge of the cell
+			case down(r, c): // Border
+				sb.WriteString("│")
+			case region(r, c) == '.': // Empty area continues
+				sb.WriteString("░")
+			default: // Inside a piece
+				sb.WriteByte(' ')
+			}
+			switch { // The cell itself
+			case c == b.Size: // Past the right frame
+			case b.Grid[r][c] == '.': // Empty cell
+				sb.WriteString("░░░")
+			default: // Letter, centred
+				sb.WriteString(" " + string(b.Grid[r][c]) + " ")
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// ansi matches SGR escape sequences.
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// colouredEmpty matches an empty cell inside a coloured run.
+var colouredEmpty = regexp.MustCompile("\x1b\\[30;[0-9;]*m[^\x1b]*\\.")
+
+// TestBoard_ColorString checks family colours, shading of touching pieces of
+// one family, and that only escapes are added.
+func TestBoard_ColorString(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		want  []string // Coloured runs the output must contain
+	}{
+		{
+			name:  "families",
+			board: "AAAA\nBBC.\nBBC.\n..CC\n", // I, O, L
+			want:  []string{"\x1b[30;46mAAAA\x1b[0m", "\x1b[30;43mBB\x1b[0m", "\x1b[30;48;5;208mCC\x1b[0m"},
+		},
+		{
+			name:  "touching squares",
+			board: "AABB\nAABB\n....\n....\n",
+			want:  []string{"\x1b[30;43mAA\x1b[0m\x1b[30;103mBB\x1b[0m"},
+		},
+		{
+			name:  "chain of squares", // Shaded along the chain, not in label order
+			board: "AACCDDBB\nAACCDDBB\n........\n........\n........\n........\n........\n........\n",
+			want:  []string{"\x1b[30;43mAA\x1b[0m\x1b[30;103mCC\x1b[0m\x1b[30;43mDD\x1b[0m\x1b[30;103mBB\x1b[0m"},
+		},
+		{
+			name:  "separate squares",
+			board: "AA.BB\nAA.BB\n.....\n.....\n.....\n",
+			want:  []string{"\x1b[30;43mAA\x1b[0m.\x1b[30;43mBB\x1b[0m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseBoard(strings.NewReader(tt.board))
+			if err != nil {
+				t.Fatalf("ParseBoard() error: %v", err)
+			}
+			got := b.ColorString()
+			if plain := ansi.ReplaceAllString(got, ""); plain != tt.board {
+				t.Errorf("ColorString() without escapes = %q, want %q", plain, tt.board)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("ColorString() = %q, want it to contain %q", got, want)
+				}
+			}
+			if colouredEmpty.MatchString(got) {
+				t.Errorf("ColorString() colours empty cells: %q", got)
+			}
+		})
+	}
+}
+
+// TestBoard_BoxString checks outlines between pieces, merged empty areas and the frame.
+func TestBoard_BoxString(t *testing.T) {
+	tests := []struct {
+		name  string
+		board string
+		want  string
+	}{
+		{
+			name:  "empty",
+			board: "",
+			want:  "",
+		},
+		{
+			name:  "squares over a gap",
+			board: "AABB\nAABB\n....\n....\n",
+			want: "┌───────┬───────┐\n" +
+				"│ A   A │ B   B │\n" +
+				"│       │       │\n" +
+				"│ A   A │ B   B │\n" +
+				"├───────┴───────┤\n" +
+				"│░░░░░░░░░░░░░░░│\n" +
+				"│░░░░░░░░░░░░░░░│\n" +
+				"│░░░░░░░░░░░░░░░│\n" +
+				"└───────────────┘\n",
+		},
+		{
+			name:  "bend",
+			board: "A..B\nA..B\nAA.B\n...B\n",
+			want: "┌───┬───────┬───┐\n" +
+				"│ A │░░░░░░░│ B │\n" +
+				"│   │░░░░░░░│   │\n" +
+				"│ A │░░░░░░░│ B │\n" +
+				"│   └───┐░░░│   │\n" +
+				"│ A   A │░░░│ B │\n" +
+				"├───────┘░░░│   │\n" +
+				"│░░░░░░░░░░░│ B │\n" +
+				"└───────────┴───┘\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBoard(0)
+			if tt.board != "" {
+				var err error
+				if b, err = ParseBoard(strings.NewReader(tt.board)); err != nil {
+					t.Fatalf("ParseBoard() error: %v", err)
+				}
+			}
+			if got := b.BoxString(); got != tt.want {
+				t.Errorf("BoxString() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Package internal implements randomized backtracking with restarts.
+package internal
+
+import "math/rand"
+
+// restartUnit is the node budget of one step of the Luby restart schedule.
+const restartUnit = 1000
+
+// solveRestarts runs randomized searches of one board size with growing node
+// budgets (restartUnit times the Luby sequence). Each run shuffles the piece
+// order and, at every node, the order of the candidate positions, so a run
+// stuck in a huge fruitless subtree is abandoned and a different part of the
+// tree is tried. Budgets grow without bound, so a run eventually finishes within
+// its budget: then no solution means none exists. Returns true if a solution was
+// placed on b.
+func (s *search) solveRestarts(b *Board, rng *rand.Rand) bool {
+	pieces := s.pieces      // Input order, restored for the caller
+	for run := 1; ; run++ { // One run per Luby step
+		s.pieces = append([]*Tetromino{}, pieces...)                                                       // Fresh copy for this run
+		rng.Shuffle(len(s.pieces), func(i, j int) { s.pieces[i], s.pieces[j] = s.pieces[j], s.pieces[i] }) // Random piece order
+		s.budget = s.nodes + luby(run)*restartUnit                                                         // Node budget for this run
+		s.cutoff = false                                                                                   // Not cut off yet
+
+		attempt := NewBoard(b.Size) // Each run starts empty
+		if s.solveRandom(attempt, 0, rng) {
+			*b = *attempt // Copy the solution out
+			return true
+		}
+		if s.ctx.Err() != nil || !s.cutoff { // Cancelled, or the whole tree fit in the budget
+			return false
+		}
+	}
+}
+
+// solveRandom is solve with candidate positions tried in random order and a
+// node budget; s.cutoff records whether the budget ran out.
+func (s *search) solveRandom(b *Board, idx int, rng *rand.Rand) bool {
+	select {
+	case <-s.ctx.Done(): // Check for cancellation periodically
+		return false
+	default: // Continue if not cancelled
+	}
+	s.publish(b, idx)
+
+	if idx >= len(s.pieces) { // All pieces placed successfully
+		return true
+	}
+	if s.nodes >= s.budget { // Abandon this run; the caller restarts
+		s.cutoff = true
+		return false
+	}
+
+	piece := s.pieces[idx]              // Piece to place at this depth
+	var cells []Point                   // Legal positions for this piece
+	for row := 0; row < b.Size; row++ { // Try each row position
+		for col := 0; col < b.Size; col++ { // Try each column position
+			if b.CanPlace(piece, row, col) { // Check if piece can be placed here
+				cells = append(cells, Point{Row: row, Col: col}) // Candidate position
+			}
+		}
+	}
+	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] }) // Random position order
+
+	for _, c := range cells { // Each candidate in turn
+		newBoard := b.Copy()                // Create board copy for backtracking
+		newBoard.Place(piece, c.Row, c.Col) // Place piece on the copy
+		s.nodes++                           // Count the node
+
+		if s.solveRandom(newBoard, idx+1, rng) {
+			*b = *newBoard // Propagate successful solution back up the call stack
+			return true
+		}
+		if s.cutoff || s.ctx.Err() != nil { // Run abandoned or cancelled
+			return false
+		}
+	}
+	return false
+}
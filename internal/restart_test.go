@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+// TestSolveWith_RestartsReproducible checks that a seed fixes the board and the work done.
+func TestSolveWith_RestartsReproducible(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:11]
+	ctx := context.Background()
+
+	first := SolveWith(ctx, pieces, Options{Restarts: true, Seed: 42})
+	second := SolveWith(ctx, pieces, Options{Restarts: true, Seed: 42})
+	if first.Board == nil || second.Board == nil {
+		t.Fatalf("SolveWith(Restarts) = %+v / %+v, want boards", first.Sizes, second.Sizes)
+	}
+	if first.Board.String() != second.Board.String() {
+		t.Errorf("same seed gave different boards:\n%s\n%s", first.Board, second.Board)
+	}
+	if first.Sizes[0].Nodes != second.Sizes[0].Nodes {
+		t.Errorf("same seed gave %d and %d nodes", first.Sizes[0].Nodes, second.Sizes[0].Nodes)
+	}
+}
+
+// TestSolveWith_RestartsOutcomes checks minimal sizes and exhaustion proofs across seeds.
+func TestSolveWith_RestartsOutcomes(t *testing.T) {
+	inputs := []struct {
+		name   string
+		pieces []*Tetromino
+	}{
+		{"L and J", logPieces}, // 3x3 must be proven exhausted
+		{"hard example minus one", parsePiecesFromString(t, hardExample)[:11]},
+	}
+
+	for _, in := range inputs {
+		t.Run(in.name, func(t *testing.T) {
+			ctx := context.Background()
+			want := Solve(ctx, in.pieces)
+			for seed := int64(0); seed < 5; seed++ {
+				got := SolveWith(ctx, in.pieces, Options{Restarts: true, Seed: seed})
+				if got.Board == nil {
+					t.Fatalf("seed %d: SolveWith(Restarts) = %+v, want a board", seed, got.Sizes)
+				}
+				if err := Verify(in.pieces, got.Board); err != nil {
+					t.Errorf("seed %d: board invalid: %v\n%s", seed, err, got.Board)
+				}
+				if len(got.Sizes) != len(want.Sizes) {
+					t.Fatalf("seed %d: sizes = %+v, want %+v", seed, got.Sizes, want.Sizes)
+				}
+				for i := range want.Sizes {
+					if got.Sizes[i].Outcome != want.Sizes[i].Outcome {
+						t.Errorf("seed %d: Sizes[%d] = %+v, want %v", seed, i, got.Sizes[i], want.Sizes[i].Outcome)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSolveRestarts_ExhaustsLargeTree checks that a size whose tree exceeds the
+// first budgets is still proven to have no solution.
+func TestSolveRestarts_ExhaustsLargeTree(t *testing.T) {
+	var pieces []*Tetromino // 8 pieces whose 6x6 search tree has about 30000 nodes in input order
+	for i, id := range []int{4, 7, 4, 10, 9, 17, 8, 9} {
+		pieces = append(pieces, &Tetromino{Label: byte('A' + i), Coords: CanonicalShapes[id]})
+	}
+
+	want := SolveSize(context.Background(), pieces, 6, Options{})
+	got := SolveSize(context.Background(), pieces, 6, Options{Restarts: true, Seed: 1})
+	if want.Sizes[0].Outcome != Exhausted {
+		t.Fatalf("SolveSize() = %+v, want exhausted", want.Sizes[0])
+	}
+	if got.Sizes[0].Outcome != Exhausted {
+		t.Errorf("SolveSize(Restarts) = %+v, want exhausted", got.Sizes[0])
+	}
+}
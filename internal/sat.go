@@ -0,0 +1,481 @@
+// Package internal implements a small CDCL SAT solver for the placement encoding.
+package internal
+
+import (
+	"context"
+	"fmt"
+)
+
+// satSolver is a conflict-driven clause learning solver: two-watched-literal unit
+// propagation, first-UIP clause learning with non-chronological backjumping,
+// activity-based decisions with phase saving, and Luby restarts.
+//
+// Literals are encoded as 2*var + sign (sign 1 = negated), with vars 0-based.
+type satSolver struct {
+	ctx      context.Context
+	clauses  [][]int   // Original and learnt clauses; watched literals are at positions 0 and 1
+	learnts  int       // Number of learnt clauses currently stored
+	watches  [][]int   // Literal -> indices of clauses watching it
+	assign   []int8    // Var -> 0 unassigned, 1 true, -1 false
+	level    []int     // Var -> decision level it was assigned at
+	reason   []int     // Var -> clause that implied it, -1 for decisions
+	trail    []int     // Assigned literals in assignment order
+	trailLim []int     // Trail length at the start of each decision level
+	qhead    int       // Next trail position to propagate
+	activity []float64 // Var -> conflict involvement score
+	varInc   float64   // Current activity bump
+	phase    []bool    // Var -> last assigned value, reused on decisions
+	seen     []bool    // Scratch marks for conflict analysis
+
+	conflicts int64 // Conflicts so far
+	decisions int64 // Decisions so far
+}
+
+const (
+	satRestartBase = 100     // Conflicts in one Luby unit
+	satVarDecay    = 0.95    // Activity decay per conflict
+	satMaxLearnts  = 20000   // Learnt clauses kept before cleaning at a restart
+	satCheckEvery  = 256     // Decisions/conflicts between cancellation checks
+	satRescale     = 1e100   // Activity value that triggers rescaling
+	satUndef       = int8(0) // Unassigned variable
+)
+
+// newSATSolver loads clauses in DIMACS literal form (±var, 1-based).
+// Returns nil if the clauses are trivially unsatisfiable.
+func newSATSolver(ctx context.Context, numVars int, clauses [][]int) *satSolver {
+	s := &satSolver{ // Per-variable state sized up front
+		ctx:      ctx,
+		watches:  make([][]int, 2*numVars),
+		assign:   make([]int8, numVars),
+		level:    make([]int, numVars),
+		reason:   make([]int, numVars),
+		activity: make([]float64, numVars),
+		varInc:   1,
+		phase:    make([]bool, numVars), // Start false: most placements are unused
+		seen:     make([]bool, numVars),
+	}
+
+	for _, dimacs := range clauses { // Each input clause
+		c := make([]int, 0, len(dimacs)) // Internal literal form
+		for _, lit := range dimacs {     // Convert each literal
+			if lit > 0 { // Positive
+				c = append(c, 2*(lit-1))
+			} else { // Negative
+				c = append(c, 2*(-lit-1)+1)
+			}
+		}
+		switch len(c) {
+		case 0: // Empty clause can never be satisfied
+			return nil
+		case 1: // Unit clause: assign at level 0
+			switch s.value(c[0]) {
+			case -1: // Already false: contradiction
+				return nil
+			case 0: // Unassigned
+				s.enqueue(c[0], -1)
+			}
+		default: // Two or more literals
+			s.attach(c) // Watch the first two
+		}
+	}
+	return s
+}
+
+// value returns 1 if the literal is true, -1 if false, 0 if unassigned.
+func (s *satSolver) value(lit int) int8 {
+	v := s.assign[lit>>1] // Variable's value
+	if lit&1 == 1 {       // Negated literal
+		return -v
+	}
+	return v
+}
+
+// enqueue makes lit true at the current level, recording the implying clause.
+func (s *satSolver) enqueue(lit, reason int) {
+	v := lit >> 1   // Variable of the literal
+	s.assign[v] = 1 // True unless negated
+	if lit&1 == 1 { // Negated literal
+		s.assign[v] = -1
+	}
+	s.level[v] = len(s.trailLim)   // Current decision level
+	s.reason[v] = reason           // Clause that implied it
+	s.trail = append(s.trail, lit) // Record the assignment
+}
+
+// attach stores a clause of two or more literals and watches its first two.
+func (s *satSolver) attach(c []int) int {
+	ci := len(s.clauses)                          // Index of the new clause
+	s.clauses = append(s.clauses, c)              // Store the clause
+	s.watches[c[0]] = append(s.watches[c[0]], ci) // First watch
+	s.watches[c[1]] = append(s.watches[c[1]], ci) // Second watch
+	return ci
+}
+
+// propagate performs unit propagation over the trail.
+// Returns the index of a conflicting clause, or -1.
+func (s *satSolver) propagate() int {
+	for s.qhead < len(s.trail) { // Until every assignment is propagated
+		falseLit := s.trail[s.qhead] ^ 1 // Clauses watching this literal lost a watch
+		s.qhead++                        // Advance the queue
+
+		ws := s.watches[falseLit]      // Clauses watching the literal
+		kept := ws[:0]                 // Watches that stay on falseLit, filtered in place
+		for i := 0; i < len(ws); i++ { // Each watching clause
+			ci := ws[i]           // Clause index
+			c := s.clauses[ci]    // Its literals
+			if c[0] == falseLit { // Keep the false watch at position 1
+				c[0], c[1] = c[1], c[0]
+			}
+			if s.value(c[0]) == 1 { // Clause already satisfied by the other watch
+				kept = append(kept, ci)
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(c); k++ { // Look for a replacement watch
+				if s.value(c[k]) != -1 { // Not false
+					c[1], c[k] = c[k], c[1]                       // Move it to the watch position
+					s.watches[c[1]] = append(s.watches[c[1]], ci) // Watch the new literal
+					moved = true                                  // Dropped from falseLit's list
+					break
+				}
+			}
+			if moved { // Watch moved elsewhere
+				continue
+			}
+
+			kept = append(kept, ci)  // Still watched here
+			if s.value(c[0]) == -1 { // Every literal false: conflict
+				kept = append(kept, ws[i+1:]...) // Keep the remaining watches
+				s.watches[falseLit] = kept
+				return ci
+			}
+			s.enqueue(c[0], ci) // Unit: the other watch must be true
+		}
+		s.watches[falseLit] = kept // Filtered list
+	}
+	return -1
+}
+
+// analyze derives a first-UIP learnt clause from a conflict.
+// Returns the clause (asserting literal first) and the level to backjump to.
+func (s *satSolver) analyze(confl int) ([]int, int) {
+	learnt := []int{0}         // Slot 0 is filled with the asserting literal at the end
+	current := len(s.trailLim) // Decision level of the conflict
+	pending := 0               // Marked literals of the current level not yet resolved
+	p := -1                    // Literal being resolved on; -1 for the conflict clause itself
+	idx := len(s.trail) - 1    // Trail position being examined
+
+	for { // Resolve until one literal of this level is left
+		c := s.clauses[confl] // Clause being resolved
+		start := 0
+		if p != -1 { // Reason clauses hold the implied literal at position 0
+			start = 1
+		}
+		for _, q := range c[start:] { // Every literal of the clause
+			v := q >> 1                       // Its variable
+			if s.seen[v] || s.level[v] == 0 { // Already counted, or fixed forever
+				continue
+			}
+			s.seen[v] = true           // Mark it
+			s.bump(v)                  // Involved in a conflict
+			if s.level[v] >= current { // Current level
+				pending++ // Resolve it away later
+			} else { // Earlier level
+				learnt = append(learnt, q) // Part of the learnt clause
+			}
+		}
+
+		for !s.seen[s.trail[idx]>>1] { // Walk back to the next marked literal
+			idx--
+		}
+		p = s.trail[idx]     // Literal to resolve on
+		idx--                // Step past it
+		s.seen[p>>1] = false // Resolved
+		pending--            // One fewer pending
+		if pending == 0 {    // p is the first unique implication point
+			break
+		}
+		confl = s.reason[p>>1] // Resolve with its reason
+	}
+	learnt[0] = p ^ 1 // Asserting literal: the UIP negated
+
+	backjump := 0                      // Highest remaining level
+	for i := 1; i < len(learnt); i++ { // Second watch goes on the highest remaining level
+		s.seen[learnt[i]>>1] = false
+		if lv := s.level[learnt[i]>>1]; lv > backjump { // Higher level found
+			backjump = lv                               // Backjump there
+			learnt[1], learnt[i] = learnt[i], learnt[1] // Watch this literal second
+		}
+	}
+	return learnt, backjump
+}
+
+// bump raises a variable's activity, rescaling all activities on overflow.
+func (s *satSolver) bump(v int) {
+	s.activity[v] += s.varInc       // Bump the score
+	if s.activity[v] > satRescale { // Overflow looming
+		for i := range s.activity { // Scale every activity down
+			s.activity[i] /= satRescale
+		}
+		s.varInc /= satRescale // And the bump with them
+	}
+}
+
+// backtrack undoes assignments above the given level, saving their phases.
+func (s *satSolver) backtrack(level int) {
+	if len(s.trailLim) <= level { // Nothing above that level
+		return
+	}
+	start := s.trailLim[level]                   // First trail entry above the level
+	for i := len(s.trail) - 1; i >= start; i-- { // Undo newest first
+		v := s.trail[i] >> 1          // Its variable
+		s.phase[v] = s.assign[v] == 1 // Remember the phase
+		s.assign[v] = satUndef        // Unassign
+	}
+	s.trail = s.trail[:start]       // Drop the undone assignments
+	s.trailLim = s.trailLim[:level] // Drop the levels
+	s.qhead = start                 // Repropagate from here
+}
+
+// decide picks the unassigned variable with the highest activity.
+// Returns false when every variable is assigned.
+func (s *satSolver) decide() bool {
+	best := -1                   // No variable chosen yet
+	for v, a := range s.assign { // Every variable
+		if a == satUndef && (best < 0 || s.activity[v] > s.activity[best]) { // Unassigned and more active
+			best = v
+		}
+	}
+	if best < 0 { // Complete assignment
+		return false
+	}
+
+	s.decisions++                                 // Count the decision
+	s.trailLim = append(s.trailLim, len(s.trail)) // Open a new level
+	lit := 2*best + 1                             // Negative literal unless the saved phase is true
+	if s.phase[best] {                            // Saved phase is true
+		lit = 2 * best // Positive literal
+	}
+	s.enqueue(lit, -1) // Decision: no reason clause
+	return true
+}
+
+// clean drops learnt clauses longer than three literals and clauses satisfied at
+// level 0, then rebuilds all watches. Only called at level 0, where no assigned
+// variable's reason is needed again.
+func (s *satSolver) clean(original int) {
+	var kept [][]int               // Clauses surviving the clean
+	learnts := 0                   // Learnt clauses among them
+	for ci, c := range s.clauses { // Every clause
+		if ci >= original && len(c) > 3 { // Long learnt clause
+			continue
+		}
+		satisfied := false
+		for _, lit := range c { // Any true literal satisfies it
+			if s.value(lit) == 1 { // True at level 0
+				satisfied = true
+				break
+			}
+		}
+		if satisfied { // Satisfied forever
+			continue
+		}
+		if ci >= original { // Learnt clause
+			learnts++
+		}
+		kept = append(kept, c) // Kept
+	}
+
+	s.clauses = nil            // Rebuild from scratch
+	for i := range s.watches { // Every literal's watches
+		s.watches[i] = s.watches[i][:0] // Keep the storage
+	}
+	for _, c := range kept { // Propagation at level 0 is complete, so the first two literals are unassigned
+		s.attach(c)
+	}
+	s.learnts = learnts // Learnt clauses kept
+}
+
+// solve runs the CDCL loop. Returns the model (true/false per var) and whether
+// the formula is satisfiable; ok is false if the context was cancelled first.
+func (s *satSolver) solve() (model []bool, sat bool, ok bool) {
+	original := len(s.clauses)               // Clauses at or after this index are learnt (before any clean)
+	restart := 1                             // Index into the Luby sequence
+	budget := luby(restart) * satRestartBase // Conflicts before the first restart
+
+	for { // Propagate, learn, restart, decide
+		if (s.conflicts+s.decisions)%satCheckEvery == 0 && s.ctx.Err() != nil { // Every so often, check for cancellation
+			return nil, false, false
+		}
+
+		confl := s.propagate() // Unit propagation
+		if confl >= 0 {        // Conflict
+			s.conflicts++             // Count the conflict
+			if len(s.trailLim) == 0 { // Conflict without decisions: unsatisfiable
+				return nil, false, true
+			}
+			learnt, level := s.analyze(confl) // First-UIP clause and backjump level
+			s.backtrack(level)                // Backjump
+			if len(learnt) == 1 {             // Unit learnt clause
+				s.enqueue(learnt[0], -1) // Level 0 after backtracking
+			} else { // Longer learnt clause
+				s.enqueue(learnt[0], s.attach(learnt)) // Asserted by the learnt clause
+				s.learnts++                            // One more learnt clause
+			}
+			s.varInc /= satVarDecay // Older activity weighs less
+			budget--                // One conflict of the budget spent
+			continue
+		}
+
+		if budget <= 0 { // Restart: keep learnt clauses, forget the current branch
+			s.backtrack(0)
+			if s.propagate() >= 0 { // Level-0 conflict from clauses learnt since the last restart
+				return nil, false, true
+			}
+			if s.learnts > satMaxLearnts { // Too many learnt clauses
+				s.clean(original)                     // Drop the long ones
+				original = len(s.clauses) - s.learnts // Learnt clauses are now at the end
+			}
+			restart++                               // Next Luby step
+			budget = luby(restart) * satRestartBase // New conflict budget
+		}
+
+		if !s.decide() { // All variables assigned without conflict
+			model = make([]bool, len(s.assign)) // Model from the assignment
+			for v, a := range s.assign {        // Every variable
+				model[v] = a == 1 // True or false
+			}
+			return model, true, true
+		}
+	}
+}
+
+// luby returns the i-th element (1-based) of the Luby restart sequence:
+// 1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, ...
+func luby(i int) int64 {
+	for k := 1; ; k++ { // Blocks of length 2^k-1
+		if i == (1<<k)-1 { // End of a block: 2^(k-1)
+			return int64(1) << (k - 1)
+		}
+		if i < (1<<k)-1 { // Inside the block: recurse into its repeated prefix
+			return luby(i - (1 << (k - 1)) + 1)
+		}
+	}
+}
+
+// orderIdentical breaks the symmetry between pieces of the same shape: of any
+// two such pieces, the one listed first must take the earlier placement. Every
+// solution has a relabelled twin that satisfies this, so satisfiability is
+// unchanged, but the solver no longer explores each permutation separately.
+func orderIdentical(f *CNF) [][]int {
+	vars := make(map[*Tetromino][]int) // Piece -> its placement variables, in placement order
+	for i, p := range f.Placements {
+		vars[p.Piece] = append(vars[p.Piece], i+1) // Variables numbered from 1
+	}
+
+	var clauses [][]int
+	for i, a := range f.pieces { // Every pair of pieces
+		for _, b := range f.pieces[i+1:] { // Later pieces only
+			if ShapeID(a.Coords) != ShapeID(b.Coords) { // Different shapes need no ordering
+				continue
+			}
+			va, vb := vars[a], vars[b] // Same shape, so placement k of each is the same position
+			for k := range va {        // Placement k of a
+				for m := 0; m <= k; m++ { // a at k forbids b at or before k
+					clauses = append(clauses, []int{-va[k], -vb[m]})
+				}
+			}
+		}
+	}
+	return clauses
+}
+
+// limitEmpty adds the implied bound "at most size² - 4n cells stay empty", which
+// lets a near-perfect fit propagate as soon as too many cells are cut off. Cell
+// c gets an auxiliary variable that must be true if nothing covers c, and the
+// auxiliaries are limited with a sequential counter. Returns the clauses and
+// the total variable count including auxiliaries; with no room to spare the
+// formula's own coverage clauses already say everything.
+func limitEmpty(f *CNF) ([][]int, int) {
+	cells := f.Size * f.Size     // Cells on the board
+	k := cells - 4*len(f.pieces) // Empty cells allowed
+	next := f.NumVars()          // Last variable allocated
+	if k <= 0 || k >= cells {    // No room for empty cells, or no pieces
+		return nil, next
+	}
+
+	covering := make([][]int, cells) // Cell index -> placements covering it
+	for i, p := range f.Placements { // Every placement
+		for _, c := range p.Piece.Coords { // Every cell of the piece
+			cell := (p.Row+c.Row)*f.Size + p.Col + c.Col // Cell index on the board
+			covering[cell] = append(covering[cell], i+1)
+		}
+	}
+
+	var clauses [][]int
+	empty := make([]int, cells)        // Cell index -> "cell is empty" variable
+	for cell, vars := range covering { // Every cell
+		next++                                                            // New variable
+		empty[cell] = next                                                // Cell is empty
+		clauses = append(clauses, append(append([]int{}, vars...), next)) // Covered, or counted as empty
+	}
+
+	// Sequential counter: count[i][j] is true if at least j+1 of empty[0..i] are true.
+	count := make([][]int, cells) // One row of counters per cell
+	for i := range count {        // Every cell
+		count[i] = make([]int, k) // Up to k counters
+		for j := range count[i] { // Allocate the counters
+			next++ // New variable
+			count[i][j] = next
+		}
+	}
+	for i, e := range empty { // Every cell in order
+		clauses = append(clauses, []int{-e, count[i][0]}) // This cell counts
+		if i == 0 {
+			for j := 1; j < k; j++ {
+				clauses = append(clauses, []int{-count[0][j]}) // One cell cannot count twice
+			}
+			continue
+		}
+		for j := 0; j < k; j++ { // Every count
+			clauses = append(clauses, []int{-count[i-1][j], count[i][j]}) // Counts carry forward
+			if j > 0 {                                                    // Count above one
+				clauses = append(clauses, []int{-e, -count[i-1][j-1], count[i][j]}) // And add up
+			}
+		}
+		clauses = append(clauses, []int{-e, -count[i-1][k-1]}) // The k+1-th empty cell is not allowed
+	}
+	return clauses, next
+}
+
+// solveSAT searches one board size with the CDCL solver over the CNF encoding.
+// Returns the board (nil if none), the number of decisions, whether the search
+// finished before the context was cancelled, and an error if the model found
+// does not decode to a valid board.
+func solveSAT(ctx context.Context, pieces []*Tetromino, size int) (*Board, int64, bool, error) {
+	f := EncodeCNF(pieces, size)                               // Placement encoding
+	clauses := append(f.Clauses, orderIdentical(f)...)         // Plus the symmetry-breaking clauses
+	extra, numVars := limitEmpty(f)                            // Plus the empty-cell limit
+	s := newSATSolver(ctx, numVars, append(clauses, extra...)) // Load every clause
+	if s == nil {                                              // Trivially unsatisfiable
+		return nil, 0, true, nil
+	}
+
+	model, sat, ok := s.solve() // Run the CDCL loop
+	if !ok || !sat {            // Cancelled or unsatisfiable
+		return nil, s.decisions, ok, nil
+	}
+
+	var lits []int                            // Placement variables set to true
+	for v, val := range model[:f.NumVars()] { // Auxiliary variables are not placements
+		if val { // Placement used
+			lits = append(lits, v+1) // DIMACS numbering
+		}
+	}
+	b, err := f.Decode(lits) // Board from the placements
+	if err != nil {          // A model of the encoding always decodes; anything else is a solver bug
+		return nil, s.decisions, true, fmt.Errorf("sat model at size %d: %w", size, err)
+	}
+	return b, s.decisions, true, nil
+}
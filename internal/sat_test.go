@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSATSolver checks satisfiability of small hand-written formulas.
+func TestSATSolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		numVars int
+		clauses [][]int
+		wantSAT bool
+	}{
+		{"empty formula", 2, nil, true},
+		{"unit chain", 3, [][]int{{1}, {-1, 2}, {-2, 3}}, true},
+		{"contradicting units", 1, [][]int{{1}, {-1}}, false},
+		{"empty clause", 1, [][]int{{}}, false},
+		{"all four 2-clauses", 2, [][]int{{1, 2}, {1, -2}, {-1, 2}, {-1, -2}}, false},
+		{"pigeonhole 3 into 2", 6, [][]int{ // Var 2p+h+1: pigeon p in hole h
+			{1, 2}, {3, 4}, {5, 6},
+			{-1, -3}, {-1, -5}, {-3, -5},
+			{-2, -4}, {-2, -6}, {-4, -6},
+		}, false},
+		{"pigeonhole 2 into 2", 4, [][]int{
+			{1, 2}, {3, 4},
+			{-1, -3}, {-2, -4},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSATSolver(context.Background(), tt.numVars, tt.clauses)
+			if s == nil {
+				if tt.wantSAT {
+					t.Fatal("newSATSolver() = nil, want satisfiable")
+				}
+				return
+			}
+
+			model, sat, ok := s.solve()
+			if !ok {
+				t.Fatal("solve() did not finish")
+			}
+			if sat != tt.wantSAT {
+				t.Fatalf("solve() sat = %v, want %v", sat, tt.wantSAT)
+			}
+			if !sat {
+				return
+			}
+			for _, clause := range tt.clauses {
+				satisfied := false
+				for _, lit := range clause {
+					if (lit > 0) == model[abs(lit)-1] {
+						satisfied = true
+					}
+				}
+				if !satisfied {
+					t.Errorf("model %v violates clause %v", model, clause)
+				}
+			}
+		})
+	}
+}
+
+func TestLuby(t *testing.T) {
+	want := []int64{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, 1}
+	for i, w := range want {
+		if got := luby(i + 1); got != w {
+			t.Errorf("luby(%d) = %d, want %d", i+1, got, w)
+		}
+	}
+}
+
+// TestSolveWith_SATMatchesBacktrack compares the two backends on the size reports.
+func TestSolveWith_SATMatchesBacktrack(t *testing.T) {
+	tests := []struct {
+		name   string
+		pieces []*Tetromino
+	}{
+		{"L and J", logPieces}, // 3x3 exhausted, 4x4 solved
+		{"two O", []*Tetromino{ // 3x3 ruled out by a bound
+			{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+			{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		}},
+		{"mixed", []*Tetromino{
+			{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}}},
+			{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+			{Label: 'C', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}},
+			{Label: 'D', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}},
+			{Label: 'E', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			want := Solve(ctx, tt.pieces)
+			got := SolveWith(ctx, tt.pieces, Options{Backend: SAT})
+
+			if got.Board == nil {
+				t.Fatal("SolveWith(SAT) returned nil board")
+			}
+			if err := Verify(tt.pieces, got.Board); err != nil {
+				t.Errorf("SolveWith(SAT) board invalid: %v\n%s", err, got.Board)
+			}
+			if len(got.Sizes) != len(want.Sizes) {
+				t.Fatalf("SolveWith(SAT) sizes = %+v, want %+v", got.Sizes, want.Sizes)
+			}
+			for i := range want.Sizes {
+				if got.Sizes[i].Size != want.Sizes[i].Size || got.Sizes[i].Outcome != want.Sizes[i].Outcome {
+					t.Errorf("Sizes[%d] = %+v, want %+v", i, got.Sizes[i], want.Sizes[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSolveWith_SATHardExample solves the near-perfect fit that the backtracker
+// needs far longer for.
+func TestSolveWith_SATHardExample(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result := SolveWith(ctx, pieces, Options{Backend: SAT})
+	if result.Timeout || result.Board == nil {
+		t.Fatalf("SolveWith(SAT) timed out: %+v", result.Sizes)
+	}
+	if result.Board.Size != 7 {
+		t.Errorf("SolveWith(SAT) size = %d, want 7", result.Board.Size)
+	}
+	if err := Verify(pieces, result.Board); err != nil {
+		t.Errorf("SolveWith(SAT) board invalid: %v\n%s", err, result.Board)
+	}
+}
+
+func TestSolveWith_SATCancellation(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Cancelled before the first size
+
+	result := SolveSize(ctx, pieces, 7, Options{Backend: SAT})
+	if !result.Timeout || result.Sizes[0].Outcome != Cancelled {
+		t.Errorf("SolveSize(SAT, cancelled) = %+v, want cancelled", result.Sizes)
+	}
+}
+
+// TestOrderIdentical checks that symmetry breaking keeps a solution reachable.
+func TestOrderIdentical(t *testing.T) {
+	o := []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	pieces := []*Tetromino{{Label: 'A', Coords: o}, {Label: 'B', Coords: o}, {Label: 'C', Coords: o}, {Label: 'D', Coords: o}}
+
+	result := SolveSize(context.Background(), pieces, 4, Options{Backend: SAT})
+	if result.Board == nil {
+		t.Fatalf("SolveSize(SAT) = %+v, want solved", result.Sizes)
+	}
+	want := "AABB\nAABB\nCCDD\nCCDD\n" // Only ordering allowed: A, B, C, D in placement order
+	if got := result.Board.String(); got != want {
+		t.Errorf("board =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+// Package internal replays search logs to check the claims they make.
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LogError describes where a search log stops matching a valid search.
+type LogError struct {
+	Message string
+	Line    int // 1-indexed log line, 0 if at end of log
+}
+
+func (e *LogError) Error() string {
+	if e.Line > 0 { // Error is associated with a specific line
+		return fmt.Sprintf("%s at line %d", e.Message, e.Line)
+	}
+	return e.Message
+}
+
+// CheckLog replays a search log written via Options.Log and confirms every
+// size block in it: each node must try exactly the legal placements of its
+// piece, in row-major order, so an "exhausted" block proves the size has no
+// solution and a "solved" block ends in a complete board. Returns one report
+// per block; a block cut off by cancellation is an error. The log is read a
+// line at a time, so its length is not limited by memory.
+func CheckLog(r io.Reader, pieces []*Tetromino) ([]SizeReport, error) {
+	c := &logChecker{pieces: pieces, scanner: bufio.NewScanner(r)} // Reads the log a line at a time
+	var reports []SizeReport                                       // One report per size block
+	for {                                                          // One size block per iteration
+		fields, ok := c.next() // First line of the block
+		if !ok {               // End of log
+			if c.err != nil { // The log broke off rather than ended
+				return nil, fmt.Errorf("reading search log: %w", c.err)
+			}
+			return reports, nil
+		}
+		if len(fields) != 2 || fields[0] != "size" { // Not a size line
+			return nil, c.errorf("expected size line")
+		}
+		size, err := strconv.Atoi(fields[1]) // Board size of the block
+		if err != nil {
+			return nil, c.errorf("invalid size %q", fields[1])
+		}
+		if err := checkLogSize(size, pieces); err != nil { // Before NewBoard allocates it
+			return nil, c.errorf("%v", err)
+		}
+
+		c.nodes = 0                              // Count this block's placements
+		solved, err := c.node(NewBoard(size), 0) // Replay the whole tree
+		if err != nil {                          // Log strayed from the tree
+			return nil, err
+		}
+
+		want := Exhausted // Outcome the replay proved
+		if solved {       // Ended on a complete board
+			want = Solved
+		}
+		fields, ok = c.next()                                      // Last line of the block
+		if !ok || len(fields) != 2 || fields[0] != want.String() { // Block must end with the replayed outcome
+			return nil, c.errorf("expected %q", want.String())
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err != nil || n != c.nodes { // Node count must match the replay
+			return nil, c.errorf("node count %s does not match %d placements", fields[1], c.nodes)
+		}
+		reports = append(reports, SizeReport{Size: size, Outcome: want, Nodes: c.nodes}) // Block confirmed
+	}
+}
+
+// checkLogSize rejects a logged board size the solver could never have searched:
+// below minSize nothing fits, above maxSize the slot board always does. A
+// corrupt size would otherwise allocate an arbitrarily large board.
+func checkLogSize(size int, pieces []*Tetromino) error {
+	if lo, hi := minSize(pieces), maxSize(pieces); size < lo || size > hi { // Below the area bound, or past the slot board
+		return fmt.Errorf("size %d outside %d to %d for %d pieces", size, lo, hi, len(pieces))
+	}
+	return nil
+}
+
+// logChecker walks the log in step with a recomputed search tree.
+type logChecker struct {
+	pieces  []*Tetromino
+	scanner *bufio.Scanner
+	line    int   // 1-indexed line most recently scanned
+	last    int   // 1-indexed line most recently returned by next, 0 at end of log
+	err     error // Read error that ended the log early
+	nodes   int64 // Placements replayed in the current block
+}
+
+// node replays the subtree for placing pieces[idx] on b, mirroring search.solve.
+func (c *logChecker) node(b *Board, idx int) (bool, error) {
+	if idx >= len(c.pieces) { // All pieces placed
+		return true, nil
+	}
+
+	piece := c.pieces[idx]              // Piece to place at this depth
+	for row := 0; row < b.Size; row++ { // Try each row position
+		for col := 0; col < b.Size; col++ { // Try each column position
+			if !b.CanPlace(piece, row, col) { // The solver only logs legal placements
+				continue
+			}
+
+			fields, ok := c.next()                                                               // Next logged event
+			want := []string{"place", string(piece.Label), strconv.Itoa(row), strconv.Itoa(col)} // Placement the solver must have made
+			if !ok || strings.Join(fields, " ") != strings.Join(want, " ") {
+				return false, c.errorf("expected %q", strings.Join(want, " "))
+			}
+			c.nodes++ // Count the node
+
+			child := b.Copy()                   // Create board copy for backtracking
+			child.Place(piece, row, col)        // Place piece on copy
+			solved, err := c.node(child, idx+1) // Replay the subtree
+			if err != nil || solved {
+				return solved, err
+			}
+
+			fields, ok = c.next()                                                                   // Next logged event
+			if !ok || len(fields) != 2 || fields[0] != "undo" || fields[1] != string(piece.Label) { // Backtrack of the same piece
+				return false, c.errorf("expected \"undo %c\"", piece.Label)
+			}
+		}
+	}
+	return false, nil // Every legal placement tried and undone
+}
+
+// next returns the fields of the next non-blank, non-comment line.
+func (c *logChecker) next() ([]string, bool) {
+	for c.scanner.Scan() { // Until a meaningful line
+		c.line++                                    // Count every line
+		line := strings.TrimSpace(c.scanner.Text()) // Ignore surrounding whitespace
+		if line != "" && !strings.HasPrefix(line, "#") {
+			c.last = c.line // Remember it for errors
+			return strings.Fields(line), true
+		}
+	}
+	c.last, c.err = 0, c.scanner.Err() // End of log, or a read error
+	return nil, false
+}
+
+// errorf builds a LogError pointing at the most recently read line. If reading
+// the log failed, that error is returned instead: the log did not end, it broke.
+func (c *logChecker) errorf(format string, args ...any) error {
+	if c.err != nil { // Reading failed
+		return fmt.Errorf("reading search log: %w", c.err)
+	}
+	return &LogError{Message: fmt.Sprintf(format, args...), Line: c.last}
+}
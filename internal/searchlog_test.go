@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// logPieces are L and J: 3x3 is searched and exhausted, 4x4 solves.
+var logPieces = []*Tetromino{
+	{Label: 'A', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+	{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+}
+
+func TestLogError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *LogError
+		wantStr string
+	}{
+		{"without line", &LogError{Message: "expected \"solved\""}, "expected \"solved\""},
+		{"with line", &LogError{Message: "expected size line", Line: 3}, "expected size line at line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.wantStr {
+				t.Errorf("Error() = %q, want %q", got, tt.wantStr)
+			}
+		})
+	}
+}
+
+// TestCheckLog_RoundTrip checks that a solver log replays to the solver's own reports.
+func TestCheckLog_RoundTrip(t *testing.T) {
+	var log bytes.Buffer
+	result := SolveWith(context.Background(), logPieces, Options{Log: &log})
+
+	reports, err := CheckLog(&log, logPieces)
+	if err != nil {
+		t.Fatalf("CheckLog() error = %v", err)
+	}
+	if len(reports) != len(result.Sizes) {
+		t.Fatalf("CheckLog() returned %d reports, want %d", len(reports), len(result.Sizes))
+	}
+	for i, r := range reports {
+		want := result.Sizes[i]
+		if r.Size != want.Size || r.Outcome != want.Outcome || r.Nodes != want.Nodes {
+			t.Errorf("report %d = %+v, want %+v", i, r, want)
+		}
+	}
+	if reports[0].Outcome != Exhausted {
+		t.Errorf("report 0 outcome = %v, want exhausted", reports[0].Outcome)
+	}
+}
+
+// TestCheckLog_Tampered ensures a log that skips part of the tree is rejected.
+func TestCheckLog_Tampered(t *testing.T) {
+	var log bytes.Buffer
+	SolveSize(context.Background(), logPieces, 3, Options{Log: &log})
+	original := log.String()
+
+	tests := []struct {
+		name string
+		log  string
+	}{
+		{"missing branch", strings.Replace(original, "place A 0 1\nundo A\n", "", 1)},
+		{"wrong node count", strings.Replace(original, "exhausted 2", "exhausted 3", 1)},
+		{"claims solved", strings.Replace(original, "exhausted", "solved", 1)},
+		{"truncated", original[:strings.LastIndex(original, "undo")]},
+		{"no size line", strings.Replace(original, "size 3\n", "", 1)},
+		{"size too small", strings.Replace(original, "size 3\n", "size 2\n", 1)},
+		{"size too large", strings.Replace(original, "size 3\n", "size 1000000\n", 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CheckLog(strings.NewReader(tt.log), logPieces)
+			var lerr *LogError
+			if !errors.As(err, &lerr) {
+				t.Errorf("CheckLog() error = %v, want *LogError", err)
+			}
+		})
+	}
+}
+
+// TestCheckLog_ReadError checks that a log which cannot be read is not reported as invalid.
+func TestCheckLog_ReadError(t *testing.T) {
+	readErr := errors.New("disk failure")
+	_, err := CheckLog(io.MultiReader(strings.NewReader("size 3\n"), iotest.ErrReader(readErr)), logPieces)
+	var lerr *LogError
+	if !errors.Is(err, readErr) || errors.As(err, &lerr) {
+		t.Errorf("CheckLog() error = %v (%T), want the read error, not a LogError", err, err)
+	}
+}
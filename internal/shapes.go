@@ -138,18 +138,37 @@ func sortPoints(points []Point) {
 
 // MatchShape checks if the given coordinates match any of the 19 canonical shapes.
 func MatchShape(coords []Point) bool {
+	return ShapeID(coords) >= 0 // Known shapes have a canonical index
+}
+
+// ShapeID returns the index of the coordinates in CanonicalShapes, or -1 if none match.
+// Shapes that differ only by translation share the same ID.
+func ShapeID(coords []Point) int {
 	if len(coords) != 4 { // Early exit: tetrominoes always have exactly 4 cells
-		return false
+		return -1
 	}
 
 	normalized := Normalize(coords) // Normalize for comparison
 
-	for _, shape := range CanonicalShapes { // Compare against each canonical shape
+	for i, shape := range CanonicalShapes { // Compare against each canonical shape
 		if pointsEqual(normalized, shape) { // Found a match
-			return true
+			return i // Index of the matching shape
 		}
 	}
-	return false
+	return -1 // No canonical shape matched
+}
+
+// shapeFamilies names the tetromino of each CanonicalShapes entry, by index.
+const shapeFamilies = "IIOTTTTSSZZLLLLJJJJ"
+
+// ShapeFamily returns the tetromino letter ('I', 'O', 'T', 'S', 'Z', 'L' or
+// 'J') of the coordinates' shape, or 0 if they are not a tetromino.
+func ShapeFamily(coords []Point) byte {
+	id := ShapeID(coords) // Canonical index, -1 if none
+	if id < 0 {           // Not a tetromino
+		return 0
+	}
+	return shapeFamilies[id] // Letter of the shape's family
 }
 
 // pointsEqual checks if two sorted point slices are equal.
@@ -231,3 +231,31 @@ func shapeName(idx int) string {
 	}
 	return "unknown"
 }
+
+func TestShapeID(t *testing.T) {
+	for i, shape := range CanonicalShapes {
+		shifted := make([]Point, len(shape))
+		for j, p := range shape {
+			shifted[j] = Point{Row: p.Row + 2, Col: p.Col + 3}
+		}
+		if got := ShapeID(shifted); got != i {
+			t.Errorf("ShapeID(%s shifted) = %d, want %d", shapeName(i), got, i)
+		}
+	}
+
+	if got := ShapeID([]Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}); got != -1 {
+		t.Errorf("ShapeID(diagonal) = %d, want -1", got)
+	}
+}
+
+func TestShapeFamily(t *testing.T) {
+	for i, shape := range CanonicalShapes {
+		if got, want := ShapeFamily(shape), shapeName(i)[0]; got != want { // Names start with the family letter
+			t.Errorf("ShapeFamily(%s) = %q, want %q", shapeName(i), got, want)
+		}
+	}
+
+	if got := ShapeFamily([]Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}); got != 0 {
+		t.Errorf("ShapeFamily(diagonal) = %q, want 0", got)
+	}
+}
@@ -3,72 +3,652 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"math"
+	"math/rand"
+	"sort"
+	"time"
 )
 
 // Result represents the outcome of solving.
 type Result struct {
-	Board   *Board // Solution board (nil if timeout)
-	Timeout bool   // True if solve was cancelled or timed out
+	Board     *Board       // Solution board (nil if timeout; with a bounded Options.Search, the best found so far)
+	Solutions []*Board     // Every solution at the minimal size (SolveAll only)
+	Timeout   bool         // True if solve was cancelled or timed out
+	Sizes     []SizeReport // What happened at each board size tried, in order
+	// Checkpoint is where a cancelled default search stopped (see Options.Resume);
+	// nil for other searches and for searches that finished.
+	Checkpoint *Checkpoint
+	Strategy   string // Name of the winning strategy (SolvePortfolio only)
+	Err        error  // Why the last size's search failed; nil unless it did
+}
+
+// Outcome is the result of trying one board size.
+type Outcome int
+
+const (
+	Solved    Outcome = iota // A solution was found
+	RuledOut                 // A cheap bound proved the size infeasible; not searched
+	Exhausted                // The full search completed without a solution
+	Cancelled                // The search was stopped before it completed
+	Failed                   // The size could not be searched, or the search broke down; see SizeReport.Err
+)
+
+func (o Outcome) String() string {
+	switch o { // Name used in reports and logs
+	case Solved:
+		return "solved"
+	case RuledOut:
+		return "ruled out"
+	case Exhausted:
+		return "exhausted"
+	case Failed:
+		return "failed"
+	}
+	return "cancelled"
+}
+
+// SizeReport records what happened at one board size. For sizes without a
+// solution it is the certificate: either the bound that ruled the size out or
+// the size of the exhaustive search that found nothing.
+type SizeReport struct {
+	Size    int
+	Outcome Outcome
+	Bound   *Infeasibility // Argument that ruled the size out (RuledOut only)
+	Nodes   int64          // Placements tried while searching this size (decisions for the SAT backend)
+	Err     error          `json:"-"` // Why the search broke down (Failed only)
+
+	// Time spent on the size by CheckBounds and by the search. Not saved in
+	// checkpoints or the cache, where it would describe an earlier run.
+	BoundsTime time.Duration `json:"-"`
+	SearchTime time.Duration `json:"-"`
+}
+
+// Backend selects the search procedure used for each board size.
+type Backend int
+
+const (
+	Backtrack Backend = iota // Depth-first placement search (the default)
+	SAT                      // CDCL SAT solving of the placement encoding (see EncodeCNF)
+)
+
+func (b Backend) String() string {
+	if b == SAT { // CDCL backend
+		return "sat"
+	}
+	return "backtrack"
+}
+
+// SizeSearch is the order in which SolveWith tries board sizes.
+type SizeSearch int
+
+const (
+	Ascending  SizeSearch = iota // Smallest size first; the first solution is minimal (the default)
+	Descending                   // Down from the greedy board until a size fails
+	Bisect                       // Binary search between LowerBound and the greedy board
+)
+
+func (s SizeSearch) String() string {
+	switch s { // Name used by --search
+	case Descending:
+		return "descending"
+	case Bisect:
+		return "bisect"
+	}
+	return "ascending"
+}
+
+// Options configures how Solve searches for a solution.
+type Options struct {
+	// Canonical selects the lexicographically smallest Board.String() among all
+	// minimal-size solutions instead of the first one the backtracker finds.
+	// The result depends only on the input, never on search order or strategy.
+	Canonical bool
+
+	// Backend selects the search used per size. SAT propagates placement
+	// constraints and learns from dead ends, which pays off on dense
+	// near-perfect fits; its node counts are decisions, not placements.
+	// Ignored when Canonical is set.
+	Backend Backend
+
+	// Search selects the order in which board sizes are tried. Descending and
+	// Bisect start from a Greedy board and always hold a valid solution: if
+	// they are cancelled, Result.Board is the best board found, with Timeout
+	// set to mark it as not proven minimal. Their minimal board may differ from
+	// Ascending's unless Canonical is also set.
+	Search SizeSearch
+
+	// Order selects which piece the default backtracker places next (see
+	// PieceOrder). Ignored by Canonical, the SAT backend and SolveAll.
+	Order PieceOrder
+
+	// Restarts makes the backtracker randomized: each size is searched by runs
+	// with shuffled piece and position orders and a growing node budget (see
+	// solveRestarts), which avoids getting stuck on an unlucky fixed order. Seed
+	// makes the runs reproducible. Ignored by Canonical and the SAT backend;
+	// Order is replaced by the shuffled orders.
+	Restarts bool
+	Seed     int64
+
+	// Log, if set, receives a search log of every placement and backtrack made
+	// by the default backtracker (see CheckLog). Write errors are not reported;
+	// pass a bufio.Writer and check its Flush error. Logs can be very large.
+	// Only input order without Restarts is logged; CheckLog replays that order.
+	Log io.Writer
+
+	// Resume continues the default search (Ascending, Backtrack, InputOrder,
+	// no Restarts, not Canonical) from a checkpoint of the same pieces, taken
+	// from Result.Checkpoint or OnCheckpoint. Ignored by other searches.
+	Resume *Checkpoint
+
+	// OnCheckpoint, if set, receives the default search's frontier when it is
+	// cancelled and, if CheckpointEvery is positive, about that often while it
+	// runs, so a crash loses at most that much work.
+	OnCheckpoint    func(*Checkpoint)
+	CheckpointEvery time.Duration
+
+	// Progress, if set, is kept up to date with the size being searched and,
+	// for the backtrackers, a recent partial board, for display while the
+	// search runs. Do not share one Progress between concurrent searches.
+	Progress *Progress
+
+	// OnSizeStart and OnSizeDone, if set, are called on the solving goroutine
+	// when work on a board size begins and with its report when it ends, ruled
+	// out by CheckBounds or searched. A size may come up more than once (see
+	// Descending). They are not called by SolveAll.
+	OnSizeStart func(size int)
+	OnSizeDone  func(SizeReport)
+}
+
+// String describes the options that decide which board is found, e.g.
+// "backend=sat search=ascending order=input"; hooks and logs are left out.
+func (o Options) String() string {
+	desc := fmt.Sprintf("backend=%s search=%s order=%s", o.Backend, o.Search, o.Order) // Options every run has
+	if o.Canonical {                                                                   // Board depends on the order of solutions
+		desc += " canonical"
+	}
+	if o.Restarts { // Board depends on the seed
+		desc += fmt.Sprintf(" restarts seed=%d", o.Seed)
+	}
+	return desc
 }
 
 // Solve finds the smallest square grid that fits all tetrominoes.
 // Returns the solution board or nil if timeout/cancelled.
+//
+// Ties between solutions of the minimal size are broken by search order:
+// pieces are placed in input order, each at the first free position in
+// row-major order (top row first, then left to right), so the result is the
+// first such placement sequence that completes. Use SolveWith with
+// Options.Canonical for a result that is independent of the search strategy.
 func Solve(ctx context.Context, pieces []*Tetromino) *Result {
+	return SolveWith(ctx, pieces, Options{})
+}
+
+// SolveWith is Solve with explicit search options.
+func SolveWith(ctx context.Context, pieces []*Tetromino, opts Options) *Result {
 	if len(pieces) == 0 { // No pieces to place
 		return &Result{Board: NewBoard(0)}
 	}
 
-	minSize := int(math.Ceil(math.Sqrt(float64(4 * len(pieces))))) // Minimum size: ceil(sqrt(total_cells))
+	switch opts.Search { // Bounded searches live in anytime.go
+	case Descending: // Down from the greedy board
+		return solveDescending(ctx, pieces, opts)
+	case Bisect: // Binary search on the size
+		return solveBisect(ctx, pieces, opts)
+	}
 
-	for size := minSize; ; size++ { // Try increasing board sizes until solution found
+	res := &Result{}                                     // Filled in as sizes are tried
+	start := minSize(pieces)                             // Area bound: nothing smaller holds the cells
+	if cp := opts.Resume; cp != nil && resumable(opts) { // Pick up where the checkpoint left off
+		res.Sizes = append(res.Sizes, cp.Done...) // Reports of the sizes already finished
+		start = cp.Size                           // Size the checkpoint stopped in
+	}
+	if resumable(opts) { // Capture the frontier for Result.Checkpoint, passing it on to the caller
+		hook := opts.OnCheckpoint // Caller's hook, if any
+		opts.OnCheckpoint = func(cp *Checkpoint) {
+			cp.Done = append(cp.Done, res.Sizes...) // Sizes finished before this one
+			res.Checkpoint = cp                     // Latest frontier
+			if hook != nil {                        // Caller asked for checkpoints too
+				hook(cp)
+			}
+		}
+	}
+
+	for size := start; ; size++ { // Try increasing board sizes until solution found
 		select {
 		case <-ctx.Done(): // Check for cancellation before attempting
-			return &Result{Timeout: true}
+			if resumable(opts) { // Nothing of this size searched yet
+				opts.OnCheckpoint(newCheckpoint(pieces, nil, size, nil, 0))
+			}
+			res.Timeout = true
+			return res
 		default: // Continue if not cancelled
 		}
 
-		b := NewBoard(size)           // Create fresh board for this size
-		if solve(ctx, b, pieces, 0) { // Attempt to place all pieces
-			return &Result{Board: b} // Solution found
+		b, report := trySize(ctx, pieces, size, opts) // Bounds first, then the search
+		res.Sizes = append(res.Sizes, report)         // Record every size tried
+		switch report.Outcome {                       // What the size proved
+		case Solved: // Board found
+			res.Board = b        // Solution found
+			res.Checkpoint = nil // Periodic checkpoints are obsolete
+			return res
+		case Cancelled: // Stopped before a proof
+			res.Timeout = true // Result not proven minimal
+			return res
+		case Failed: // Search broke down; nothing proven
+			res.Err = report.Err // Report why
+			return res
+		}
+	}
+}
+
+// SolveSize tries a single board size without falling back to larger ones.
+// Board is nil when the pieces do not fit; Sizes[0] then explains why. Sizes
+// above maxSize are refused as Failed: no input needs them, and their grids
+// could exhaust memory.
+func SolveSize(ctx context.Context, pieces []*Tetromino, size int, opts Options) *Result {
+	if limit := maxSize(pieces); size > limit { // Never allocate a board larger than slotBoard
+		err := fmt.Errorf("size %d is larger than %d, which fits any %d pieces", size, limit, len(pieces)) // No input needs a board this large
+		return &Result{Sizes: []SizeReport{{Size: size, Outcome: Failed, Err: err}}, Err: err}
+	}
+	b, report := trySize(ctx, pieces, size, opts) // Bounds first, then the search
+	return &Result{Board: b, Timeout: report.Outcome == Cancelled, Sizes: []SizeReport{report}, Err: report.Err}
+}
+
+// minSize returns the smallest board that has room for every cell: ceil(sqrt(4n)).
+func minSize(pieces []*Tetromino) int {
+	return int(math.Ceil(math.Sqrt(float64(4 * len(pieces)))))
+}
+
+// maxSize returns the side of slotBoard, a board every input fits: 4*ceil(sqrt(n)).
+// No search ever needs a larger one.
+func maxSize(pieces []*Tetromino) int {
+	return 4 * int(math.Ceil(math.Sqrt(float64(len(pieces)))))
+}
+
+// trySize rules a size out with CheckBounds if it can, and searches it otherwise.
+// Returns the solution (nil if none) and a report of what happened.
+func trySize(ctx context.Context, pieces []*Tetromino, size int, opts Options) (b *Board, report SizeReport) {
+	if opts.OnSizeStart != nil { // Caller wants to know when the size starts
+		opts.OnSizeStart(size)
+	}
+	if opts.OnSizeDone != nil { // Caller wants the report
+		defer func() { opts.OnSizeDone(report) }() // Whatever the outcome
+	}
+
+	start := time.Now()              // Start of the bound check
+	inf := CheckBounds(pieces, size) // Cheap arguments first
+	checked := time.Now()            // End of the bound check
+	if inf != nil {                  // Hopeless size; skip the search
+		return nil, SizeReport{Size: size, Outcome: RuledOut, Bound: inf, BoundsTime: checked.Sub(start)}
+	}
+
+	b, report = searchSize(ctx, pieces, size, opts)                                // Passed the bounds: search it
+	report.BoundsTime, report.SearchTime = checked.Sub(start), time.Since(checked) // Time spent on each part
+	return b, report
+}
+
+// searchSize is trySize for a size that passed CheckBounds.
+func searchSize(ctx context.Context, pieces []*Tetromino, size int, opts Options) (*Board, SizeReport) {
+	if opts.Backend == SAT && !opts.Canonical { // SAT searches whole sizes at once
+		if opts.Progress == nil { // No one is watching
+			return trySAT(ctx, pieces, size)
+		}
+		opts.Progress.startSize(size, nil, false)         // No partial boards or estimate to show
+		b, report := trySAT(ctx, pieces, size)            // One CDCL run
+		opts.Progress.update(nil, 0, 0, report.Nodes, -1) // Decisions as the node count
+		return b, report
+	}
+
+	s := &search{ctx: ctx, pieces: orderPieces(pieces, opts.Order), progress: opts.Progress} // Search state for this size
+	if s.progress != nil {                                                                   // Someone is watching
+		if !opts.Restarts { // Randomized runs are cut off by budget, so their trees say nothing about the size
+			s.est = make([]branch, estimateDepth)
+		}
+		s.progress.startSize(size, NewBoard(size), s.est != nil)
+	}
+	if !opts.Canonical && !opts.Restarts && opts.Order == InputOrder { // Log format follows the default backtracker's tree
+		s.log = opts.Log
+	}
+	checkpoints := resumable(opts) && opts.OnCheckpoint != nil
+	if cp := opts.Resume; cp != nil && resumable(opts) && cp.Size == size { // Checkpoint for this size: re-enter its path
+		s.resume, s.nodes = cp.Path, cp.Nodes // Skip what it already searched
+	}
+	if checkpoints && opts.CheckpointEvery > 0 { // Periodic checkpoints asked for
+		s.every, s.saved, s.nextCheck = opts.CheckpointEvery, time.Now(), s.nodes+checkpointCheckNodes // First clock read after checkpointCheckNodes nodes
+		s.save = func(path []int, nodes int64) {                                                       // Called from inside the search
+			opts.OnCheckpoint(newCheckpoint(pieces, nil, size, path, nodes)) // Frontier of this size
+		}
+	}
+	s.logf("size %d", size) // Start of the size block
+
+	b := NewBoard(size) // Create fresh board for this size
+	var found bool      // Whether a board was found
+	switch {            // Search procedure by options
+	case opts.Canonical: // Smallest board first
+		s.slack = size*size - 4*len(pieces) // Empty cells the board can spare
+		found = s.solveCanonical(b, sortedByLabel(pieces), make([]bool, len(pieces)), 0, 0, s.slack)
+	case opts.Restarts: // Randomized orders with node budgets
+		found = s.solveRestarts(b, rand.New(rand.NewSource(opts.Seed+int64(size)))) // Each size reproducible on its own
+	case opts.Order == FewestPlacements: // Piece chosen per node
+		found = s.solveDynamic(b, make([]bool, len(pieces)), 0)
+	default: // Default backtracker
+		found = s.solve(b, 0)
+	}
+
+	if s.progress != nil { // Show the final node count and, if solved, the board
+		depth, explored := 0, s.explored() // A failed search has undone every placement
+		if found {                         // Every piece is on the board
+			depth = len(pieces)
+		} else if ctx.Err() == nil && explored >= 0 { // Exhausted: the whole tree is done
+			explored = 1 // Whole tree searched
+		}
+		s.progress.update(b, depth, s.maxDepth, s.nodes, explored) // Final snapshot
+	}
+
+	report := SizeReport{Size: size, Nodes: s.nodes} // Outcome filled in below
+	switch {                                         // What the search proved
+	case found: // Board found
+		report.Outcome = Solved // Solution found
+	case ctx.Err() != nil: // Stopped early; nothing proven
+		report.Outcome = Cancelled
+		b = nil
+		if checkpoints { // Record the frontier to resume from
+			opts.OnCheckpoint(newCheckpoint(pieces, nil, size, s.frontier(), s.nodes))
 		}
+	default: // Searched to the end
+		report.Outcome = Exhausted // Size has no solution
+		b = nil
+	}
+	s.logf("%s %d", report.Outcome, s.nodes) // End of the size block
+	return b, report
+}
+
+// trySAT is trySize for the SAT backend: one CDCL run over the size's placement encoding.
+func trySAT(ctx context.Context, pieces []*Tetromino, size int) (*Board, SizeReport) {
+	b, decisions, finished, err := solveSAT(ctx, pieces, size) // Whole size in one run
+	report := SizeReport{Size: size, Nodes: decisions}         // Decisions as the node count
+	switch {                                                   // What the run proved
+	case err != nil: // Model did not decode
+		report.Outcome, report.Err = Failed, err
+	case b != nil: // Satisfiable: board found
+		report.Outcome = Solved // Solution found
+	case !finished: // Stopped early; nothing proven
+		report.Outcome = Cancelled
+	default: // Formula unsatisfiable
+		report.Outcome = Exhausted
+	}
+	return b, report
+}
+
+// SolveAll finds every solution at the smallest square size that has one.
+// Solutions are listed in search order; Board is set to the first of them.
+func SolveAll(ctx context.Context, pieces []*Tetromino) *Result {
+	return solveAll(ctx, pieces, func(*Board) bool { return true }) // Keep every solution
+}
+
+// SolveAllUnique is SolveAll keeping one solution per equivalence class, as
+// Unique would. Equivalent boards are dropped as they are found, so memory grows
+// with the number of classes, not with the (combinatorial) number of solutions.
+func SolveAllUnique(ctx context.Context, pieces []*Tetromino) *Result {
+	return solveAll(ctx, pieces, firstOfClass(pieces)) // Keep the first board of each class
+}
+
+// solveAll is SolveAll listing only the solutions keep accepts.
+func solveAll(ctx context.Context, pieces []*Tetromino, keep func(*Board) bool) *Result {
+	if len(pieces) == 0 { // No pieces to place
+		b := NewBoard(0) // The empty board is the only solution
+		return &Result{Board: b, Solutions: []*Board{b}}
+	}
+
+	res := &Result{}                        // Filled in as sizes are tried
+	for size := minSize(pieces); ; size++ { // Try increasing board sizes until solutions found
+		start := time.Now()              // Start of the bound check
+		inf := CheckBounds(pieces, size) // Cheap arguments first
+		checked := time.Now()            // End of the bound check
+		if inf != nil {                  // Hopeless size; skip the search
+			res.Sizes = append(res.Sizes, SizeReport{Size: size, Outcome: RuledOut, Bound: inf, BoundsTime: checked.Sub(start)})
+			continue
+		}
+
+		var solutions []*Board                          // Kept solutions at this size
+		s := &search{ctx: ctx, pieces: pieces}          // Search state for this size
+		s.enumerate(NewBoard(size), 0, func(b *Board) { // Visit every complete placement
+			if keep(b) { // Accepted by the caller
+				solutions = append(solutions, b) // Boards passed to the callback are never mutated again
+			}
+		})
+		report := SizeReport{Size: size, Nodes: s.nodes, BoundsTime: checked.Sub(start), SearchTime: time.Since(checked)} // Outcome filled in below
 
 		select {
-		case <-ctx.Done(): // Check for cancellation after attempt
-			return &Result{Timeout: true}
-		default: // Continue to next size
+		case <-ctx.Done(): // Enumeration incomplete; partial lists are not reported
+			report.Outcome = Cancelled            // Stopped before the end
+			res.Sizes = append(res.Sizes, report) // Record the size
+			res.Timeout = true
+			return res
+		default: // Continue if not cancelled
+		}
+
+		if len(solutions) > 0 { // Smallest size with at least one solution
+			report.Outcome = Solved               // Solutions found
+			res.Sizes = append(res.Sizes, report) // Record the size
+			res.Board, res.Solutions = solutions[0], solutions
+			return res
 		}
+		report.Outcome = Exhausted            // Searched to the end without a solution
+		res.Sizes = append(res.Sizes, report) // Record the size and try the next
+	}
+}
+
+// search holds the state of one backtracking run over a single board size.
+type search struct {
+	ctx    context.Context
+	pieces []*Tetromino
+	nodes  int64     // Placements tried so far
+	log    io.Writer // Search log destination, nil when disabled
+
+	path      []int                     // Cell index of the placement at each depth of the current node
+	resume    []int                     // Checkpoint path still to re-enter, nil once reached
+	save      func(path []int, n int64) // Periodic checkpoint sink, nil when disabled
+	every     time.Duration             // Minimum time between periodic checkpoints
+	saved     time.Time                 // When the last periodic checkpoint was taken
+	nextCheck int64                     // Node count at which to next read the clock
+
+	budget int64 // Node count at which a randomized run is abandoned
+	cutoff bool  // Whether the current randomized run hit its budget
+
+	progress     *Progress // Live state sink, nil when disabled
+	nextProgress int64     // Node count at which to next publish
+	maxDepth     int       // Most pieces placed at once so far
+	est          []branch  // Branches taken on the shallow levels of the current path; nil without an estimate
+	level        int       // Tree depth of the current node, capped at len(est)
+	slack        int       // Empty cells of a canonical board, to turn its counts into tree depth
+}
+
+// logf writes one search log line if logging is enabled.
+func (s *search) logf(format string, args ...any) {
+	if s.log != nil { // Logging enabled
+		fmt.Fprintf(s.log, format+"\n", args...) // One line per event
 	}
 }
 
 // solve recursively places tetrominoes using backtracking.
 // Returns true if all pieces are placed successfully.
-func solve(ctx context.Context, b *Board, pieces []*Tetromino, idx int) bool {
+func (s *search) solve(b *Board, idx int) bool {
 	select {
-	case <-ctx.Done(): // Check for cancellation periodically
+	case <-s.ctx.Done(): // Check for cancellation periodically
 		return false
 	default: // Continue if not cancelled
 	}
+	s.publish(b, idx) // Report progress if due
+
+	s.path = s.path[:idx] // Frontier is this node
+	s.maybeSave()         // Checkpoint if due
 
-	if idx >= len(pieces) { // All pieces placed successfully
+	if idx >= len(s.pieces) { // All pieces placed successfully
 		return true
 	}
 
-	piece := pieces[idx] // Get current piece to place
+	piece := s.pieces[idx] // Get current piece to place
+
+	start := 0               // First cell to try; positions before a checkpoint's are done
+	if idx < len(s.resume) { // Still re-entering a checkpoint's path
+		start = s.resume[idx] // Resume at its cell
+	} else {
+		s.resume = nil // Checkpoint reached: everything deeper and later is new
+	}
+
+	k, n := 0, 0           // Branch being tried and branch count, for the progress estimate
+	if s.estimating(idx) { // Shallow node: count its branches
+		k, n = placementsBefore(b, piece, start), placementsBefore(b, piece, b.Size*b.Size) // Branches already done, and all branches
+	}
+
+	for cell := start; cell < b.Size*b.Size; cell++ { // Try each position in row-major order
+		row, col := cell/b.Size, cell%b.Size // Cell index to coordinates
+		if b.CanPlace(piece, row, col) {     // Check if piece fits here
+			s.enter(idx, k, n)                    // Progress estimate for this branch
+			k++                                   // Next branch
+			newBoard := b.Copy()                  // Create copy for immutable backtracking
+			newBoard.Place(piece, row, col)       // Place piece on copy
+			s.path = append(s.path[:idx], cell)   // Frontier is this placement
+			if s.resume == nil || cell != start { // The checkpoint already counted the placement it stopped in
+				s.nodes++ // Count the node
+			}
+			s.logf("place %c %d %d", piece.Label, row, col) // Log the placement
+
+			if s.solve(newBoard, idx+1) { // Recursively place remaining pieces
+				*b = *newBoard // Propagate successful solution back up the call stack
+				return true
+			}
+			if s.ctx.Err() != nil { // Cancelled inside the subtree; stop without logging a backtrack
+				return false
+			}
+			s.logf("undo %c", piece.Label) // Log the backtrack
+		}
+	}
+
+	return false // No valid placement found at this position
+}
+
+// enumerate recursively places tetrominoes, calling found for every complete placement.
+// Unlike solve it never stops early, so the whole search tree for the size is visited.
+func (s *search) enumerate(b *Board, idx int, found func(*Board)) {
+	select {
+	case <-s.ctx.Done(): // Check for cancellation periodically
+		return
+	default: // Continue if not cancelled
+	}
+
+	if idx >= len(s.pieces) { // All pieces placed
+		found(b) // Report the complete board
+		return
+	}
+
+	piece := s.pieces[idx] // Get current piece to place
 
 	for row := 0; row < b.Size; row++ { // Try each row position
 		for col := 0; col < b.Size; col++ { // Try each column position
 			if b.CanPlace(piece, row, col) { // Check if piece fits here
-				newBoard := b.Copy()            // Create copy for immutable backtracking
-				newBoard.Place(piece, row, col) // Place piece on copy
-
-				if solve(ctx, newBoard, pieces, idx+1) { // Recursively place remaining pieces
-					*b = *newBoard // Propagate successful solution back up the call stack
-					return true
-				}
+				newBoard := b.Copy()                // Each branch owns its board, so found boards stay intact
+				newBoard.Place(piece, row, col)     // Place piece on copy
+				s.nodes++                           // Count the node
+				s.enumerate(newBoard, idx+1, found) // Recursively place remaining pieces
 			}
 		}
 	}
+}
 
-	return false // No valid placement found at this position
+// solveCanonical fills the board cell by cell in row-major order, trying the
+// characters for each undecided cell in byte order: '.' first (while empty cells
+// remain in the budget), then each unused piece by ascending label, anchored so
+// its first cell lands on the current cell. Because each decision fixes the
+// first undecided character of Board.String(), the depth-first order of complete
+// boards is lexicographic and the first one found is the smallest.
+func (s *search) solveCanonical(b *Board, pieces []*Tetromino, used []bool, pos, placed, emptyLeft int) bool {
+	select {
+	case <-s.ctx.Done(): // Check for cancellation periodically
+		return false
+	default: // Continue if not cancelled
+	}
+	s.publish(b, placed) // Report progress if due
+
+	for pos < b.Size*b.Size && b.Grid[pos/b.Size][pos%b.Size] != '.' { // Skip cells covered by earlier pieces
+		pos++ // Next cell
+	}
+
+	if placed == len(pieces) { // All pieces placed; remaining cells stay empty
+		return true
+	}
+	if pos >= b.Size*b.Size { // Ran out of cells with pieces left
+		return false
+	}
+
+	row, col := pos/b.Size, pos%b.Size    // Cell to decide
+	depth := placed + s.slack - emptyLeft // Decisions made so far: pieces placed and cells left empty
+
+	var fits []int                 // Pieces to try here, in label order
+	tried := make(map[int]bool)    // Shape IDs already tried here; an identical shape with a larger label fails too
+	for i, piece := range pieces { // Every piece in label order
+		id := ShapeID(piece.Coords) // Shape of the piece
+		if used[i] || tried[id] {   // Placed, or an identical shape already tried
+			continue
+		}
+		tried[id] = true // Try this shape once
+
+		anchor := firstCell(piece.Coords)                      // Cell of the piece that comes first in row-major order
+		if b.CanPlace(piece, row-anchor.Row, col-anchor.Col) { // Anchor on the current cell fits
+			fits = append(fits, i) // Try it below
+		}
+	}
+
+	k, n := 0, len(fits) // Branch being tried and branch count, for the progress estimate
+	if emptyLeft > 0 {   // Empty cells still in the budget
+		n++                                                                // Leaving the cell empty is a branch too
+		s.enter(depth, k, n)                                               // Progress estimate for this branch
+		k++                                                                // Next branch
+		if s.solveCanonical(b, pieces, used, pos+1, placed, emptyLeft-1) { // '.' sorts before any label
+			return true
+		}
+	}
+
+	for _, i := range fits { // Pieces that fit, in label order
+		piece := pieces[i]                     // Piece to try
+		anchor := firstCell(piece.Coords)      // Cell of the piece that comes first in row-major order
+		r, c := row-anchor.Row, col-anchor.Col // Origin that puts the anchor on the current cell
+		s.enter(depth, k, n)                   // Progress estimate for this branch
+		k++                                    // Next branch
+
+		newBoard := b.Copy()                                                      // Create copy for immutable backtracking
+		newBoard.Place(piece, r, c)                                               // Place piece on copy
+		s.nodes++                                                                 // Count the node
+		used[i] = true                                                            // Mark it placed for the subtree
+		if s.solveCanonical(newBoard, pieces, used, pos+1, placed+1, emptyLeft) { // Recursively fill the remaining cells
+			*b = *newBoard // Propagate successful solution back up the call stack
+			return true
+		}
+		used[i] = false // Backtrack: free the piece again
+	}
+
+	return false // Neither an empty cell nor any piece works here
+}
+
+// firstCell returns the coordinate that comes first in row-major order.
+func firstCell(coords []Point) Point {
+	first := coords[0]             // Start with the first point
+	for _, p := range coords[1:] { // Compare the others
+		if p.Row < first.Row || (p.Row == first.Row && p.Col < first.Col) { // Earlier row, or same row further left
+			first = p
+		}
+	}
+	return first
+}
+
+// sortedByLabel returns the pieces ordered by ascending label.
+func sortedByLabel(pieces []*Tetromino) []*Tetromino {
+	sorted := make([]*Tetromino, len(pieces))                                            // Copy: the caller keeps input order
+	copy(sorted, pieces)                                                                 // Same pieces
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Label < sorted[j].Label }) // Ascending label
+	return sorted
 }
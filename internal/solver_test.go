@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -285,12 +286,8 @@ func TestSolve_GoodExamples(t *testing.T) {
 	}
 }
 
-// TestSolve_HardExample tests spec's hardexam (12 pieces, 1 empty space).
-func TestSolve_HardExample(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping slow test in short mode") // Can take several seconds
-	}
-	input := `....
+// hardExample is the spec's hardexam: 12 pieces that fill a 7x7 board but one cell.
+const hardExample = `....
 .##.
 .##.
 ....
@@ -351,6 +348,13 @@ func TestSolve_HardExample(t *testing.T) {
 ....
 `
 
+// TestSolve_HardExample tests spec's hardexam (12 pieces, 1 empty space).
+func TestSolve_HardExample(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping slow test in short mode") // Can take several seconds
+	}
+	input := hardExample
+
 	pieces := parsePiecesFromString(t, input)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -488,3 +492,221 @@ func verifyBoardContainsLabels(t *testing.T, output string, numPieces int) {
 		}
 	}
 }
+
+func TestSolveAll(t *testing.T) {
+	ctx := context.Background()
+
+	// Two vertical I pieces fill a 4x4 board in 4*3 = 12 ordered column pairs
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+	}
+
+	result := SolveAll(ctx, pieces)
+
+	if result.Timeout {
+		t.Fatal("SolveAll() timed out")
+	}
+	if len(result.Solutions) != 12 {
+		t.Errorf("SolveAll() found %d solutions, want 12", len(result.Solutions))
+	}
+	if result.Board != result.Solutions[0] {
+		t.Error("SolveAll() Board should be the first solution")
+	}
+	for _, b := range result.Solutions {
+		if b.Size != 4 {
+			t.Errorf("SolveAll() board size = %d, want 4", b.Size)
+		}
+		verifyAllPiecesPlaced(t, b, pieces)
+	}
+}
+
+func TestSolveAll_Cancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := SolveAll(ctx, []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	})
+
+	if !result.Timeout {
+		t.Error("SolveAll() should return timeout on cancelled context")
+	}
+}
+
+// TestSolveWith_CanonicalGolden pins canonical output; golden-file consumers depend on it never changing.
+func TestSolveWith_CanonicalGolden(t *testing.T) {
+	input := `...#
+...#
+...#
+...#
+
+....
+....
+....
+####
+
+.###
+...#
+....
+....
+
+....
+..##
+.##.
+....
+`
+	want := ".....\n.CCCA\n.DDCA\nDD..A\nBBBBA\n"
+
+	result := SolveWith(context.Background(), parsePiecesFromString(t, input), Options{Canonical: true})
+
+	if result.Timeout || result.Board == nil {
+		t.Fatal("SolveWith() returned no solution")
+	}
+	if got := result.Board.String(); got != want {
+		t.Errorf("SolveWith(Canonical) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestSolveWith_CanonicalIsSmallest checks canonical mode against brute-force enumeration.
+func TestSolveWith_CanonicalIsSmallest(t *testing.T) {
+	tests := []struct {
+		name   string
+		pieces []*Tetromino
+	}{
+		{
+			name: "O and T",
+			pieces: []*Tetromino{
+				{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+				{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+			},
+		},
+		{
+			name: "S, Z and L",
+			pieces: []*Tetromino{
+				{Label: 'A', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}},
+				{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 1}, {1, 2}}},
+				{Label: 'C', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+			},
+		},
+		{
+			name: "identical J pieces",
+			pieces: []*Tetromino{
+				{Label: 'A', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+				{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+				{Label: 'C', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			all := SolveAll(ctx, tt.pieces)
+			want := ""
+			for _, b := range all.Solutions {
+				if s := b.String(); want == "" || s < want {
+					want = s
+				}
+			}
+
+			result := SolveWith(ctx, tt.pieces, Options{Canonical: true})
+			if result.Board == nil {
+				t.Fatal("SolveWith() returned nil board")
+			}
+			if got := result.Board.String(); got != want {
+				t.Errorf("SolveWith(Canonical) =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestSolve_SizeReports checks that bound-pruned and searched sizes are both reported.
+func TestSolve_SizeReports(t *testing.T) {
+	// Two O pieces: 3x3 is ruled out by the column-stripe bound, 4x4 solves
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+
+	result := Solve(context.Background(), pieces)
+
+	if len(result.Sizes) != 2 {
+		t.Fatalf("Solve() reported %d sizes, want 2: %+v", len(result.Sizes), result.Sizes)
+	}
+	if r := result.Sizes[0]; r.Size != 3 || r.Outcome != RuledOut || r.Bound == nil || r.SearchTime != 0 {
+		t.Errorf("Sizes[0] = %+v, want size 3 ruled out with a bound, unsearched", r)
+	}
+	if r := result.Sizes[1]; r.Size != 4 || r.Outcome != Solved {
+		t.Errorf("Sizes[1] = %+v, want size 4 solved", r)
+	}
+
+	// L and J: 3x3 passes every bound and must be searched
+	pieces = []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+	}
+	result = Solve(context.Background(), pieces)
+	if len(result.Sizes) == 0 || result.Sizes[0].Outcome != Exhausted {
+		t.Errorf("Sizes = %+v, want size 3 exhausted first", result.Sizes)
+	}
+}
+
+func TestSolveSize(t *testing.T) {
+	ctx := context.Background()
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+	}
+
+	tests := []struct {
+		size        int
+		wantOutcome Outcome
+	}{
+		{2, RuledOut},  // Area
+		{3, Exhausted}, // Passes every bound, needs a search
+		{4, Solved},
+		{6, Solved},     // Larger sizes are not rejected
+		{8, Solved},     // The slot board: always fits
+		{9, Failed},     // Never needed, so refused
+		{40000, Failed}, // Refused without allocating the grid
+	}
+
+	for _, tt := range tests {
+		result := SolveSize(ctx, pieces, tt.size, Options{})
+		if len(result.Sizes) != 1 {
+			t.Fatalf("SolveSize(%d) reported %d sizes, want 1", tt.size, len(result.Sizes))
+		}
+		r := result.Sizes[0]
+		if r.Outcome != tt.wantOutcome {
+			t.Errorf("SolveSize(%d) outcome = %v, want %v", tt.size, r.Outcome, tt.wantOutcome)
+		}
+		if (result.Board != nil) != (tt.wantOutcome == Solved) {
+			t.Errorf("SolveSize(%d) board = %v, want board only when solved", tt.size, result.Board)
+		}
+		if tt.wantOutcome == Exhausted && r.Nodes == 0 {
+			t.Errorf("SolveSize(%d) nodes = 0, want search effort recorded", tt.size)
+		}
+	}
+}
+
+// TestSolveWith_SizeHooks checks every size tried is announced, then reported.
+func TestSolveWith_SizeHooks(t *testing.T) {
+	pieces := []*Tetromino{ // 3x3 ruled out by a bound, 4x4 solves
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+
+	var got []string
+	result := SolveWith(context.Background(), pieces, Options{
+		OnSizeStart: func(size int) { got = append(got, fmt.Sprintf("start %d", size)) },
+		OnSizeDone:  func(r SizeReport) { got = append(got, fmt.Sprintf("%v %d", r.Outcome, r.Size)) },
+	})
+	if result.Board == nil {
+		t.Fatal("SolveWith() found no board")
+	}
+	if want := "start 3,ruled out 3,start 4,solved 4"; strings.Join(got, ",") != want {
+		t.Errorf("hooks called %q, want %q", strings.Join(got, ","), want)
+	}
+}
@@ -0,0 +1,86 @@
+// Package internal renders boards as SVG images.
+package internal
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// SVG renders the board as a standalone SVG document, drawn like Image.
+func (b *Board) SVG(opts ImageOptions) string {
+	opts = opts.withDefaults()                             // Fill in unset fields
+	cs, w, pal := opts.CellSize, opts.Border, opts.Palette // Cell size, border width, colours
+	off := (w + 1) / 2                                     // Room for the frame's stroke
+	side := b.Size * cs                                    // Board side in pixels
+	var sb strings.Builder                                 // Document accumulator
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"%d %d %d %d\">\n",
+		side+2*off, side+2*off, -off, -off, side+2*off, side+2*off)
+	fmt.Fprintf(&sb, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", side, side, hexColor(pal.Empty)) // Background for empty cells
+
+	family, shade := b.pieceShades() // Colour choice per label
+	cells, order := b.labelCells()   // Cells of each piece, in row-major order
+	for _, label := range order {    // One path of cell squares per piece
+		var d strings.Builder            // Path data for this piece
+		for _, c := range cells[label] { // One square per cell
+			fmt.Fprintf(&d, "M%d %dh%dv%dh-%dz", c.Col*cs, c.Row*cs, cs, cs, cs) // Move, then three sides and close
+		}
+		fmt.Fprintf(&sb, "<path d=\"%s\" fill=\"%s\" shape-rendering=\"crispEdges\"/>\n",
+			d.String(), hexColor(pal.Pieces[family[label]][shade[label]]))
+	}
+
+	if opts.Grid { // Faint lines between all cells
+		var d strings.Builder
+		for i := 1; i < b.Size; i++ { // One horizontal and one vertical line per inner edge
+			fmt.Fprintf(&d, "M0 %dH%dM%d 0V%d", i*cs, side, i*cs, side)
+		}
+		fmt.Fprintf(&sb, "<path d=\"%s\" stroke=\"%s\" stroke-opacity=\"0.15\" stroke-width=\"1\"/>\n", d.String(), hexColor(pal.Border))
+	}
+
+	var d strings.Builder          // Borders between different labels, merged into runs
+	for r := 0; r <= b.Size; r++ { // Horizontal edges, top to bottom
+		for c := 0; c < b.Size; { // Advance a run at a time
+			if b.at(r-1, c) == b.at(r, c) { // No border here
+				c++
+				continue
+			}
+			start := c                                     // Run starts
+			for c < b.Size && b.at(r-1, c) != b.at(r, c) { // Extend while labels differ
+				c++
+			}
+			fmt.Fprintf(&d, "M%d %dH%d", start*cs, r*cs, c*cs) // One segment per run
+		}
+	}
+	for c := 0; c <= b.Size; c++ { // Vertical edges, left to right
+		for r := 0; r < b.Size; { // Advance a run at a time
+			if b.at(r, c-1) == b.at(r, c) { // No border here
+				r++
+				continue
+			}
+			start := r                                     // Run starts
+			for r < b.Size && b.at(r, c-1) != b.at(r, c) { // Extend while labels differ
+				r++
+			}
+			fmt.Fprintf(&d, "M%d %dV%d", c*cs, start*cs, r*cs) // One segment per run
+		}
+	}
+	if w > 0 { // Outlines asked for
+		fmt.Fprintf(&sb, "<path d=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%d\" stroke-linecap=\"square\"/>\n",
+			d.String(), hexColor(pal.Border), w)
+	}
+
+	if opts.Labels { // Letters asked for
+		for _, label := range order { // One letter per piece
+			c := centralCell(cells[label]) // Where the letter goes
+			fmt.Fprintf(&sb, "<text x=\"%d\" y=\"%d\" fill=\"%s\" font-family=\"sans-serif\" font-size=\"%d\" text-anchor=\"middle\" dominant-baseline=\"central\">%c</text>\n",
+				c.Col*cs+cs/2, c.Row*cs+cs/2, hexColor(pal.Text), cs/2, label)
+		}
+	}
+	sb.WriteString("</svg>\n") // Close the document
+	return sb.String()
+}
+
+// hexColor formats an opaque colour as #rrggbb.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestBoard_SVG checks that the SVG is well-formed and holds the requested parts.
+func TestBoard_SVG(t *testing.T) {
+	b, err := ParseBoard(strings.NewReader("A..B\nA..B\nAA.B\n...B\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      ImageOptions
+		wantSize  string
+		wantTexts int
+		wantGrid  bool
+	}{
+		{"plain", ImageOptions{}, `width="162"`, 0, false},
+		{"labels and grid", ImageOptions{Labels: true, Grid: true}, `width="162"`, 2, true},
+		{"cell size", ImageOptions{CellSize: 10}, `width="42"`, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.SVG(tt.opts)
+
+			dec := xml.NewDecoder(strings.NewReader(got))
+			elements := map[string]int{}
+			for {
+				tok, err := dec.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("SVG is not well-formed: %v\n%s", err, got)
+				}
+				if se, ok := tok.(xml.StartElement); ok {
+					elements[se.Name.Local]++
+				}
+			}
+
+			if elements["svg"] != 1 || !strings.Contains(got, tt.wantSize) {
+				t.Errorf("SVG() root = %d svg elements, want 1 with %s:\n%s", elements["svg"], tt.wantSize, got)
+			}
+			if elements["text"] != tt.wantTexts {
+				t.Errorf("SVG() has %d labels, want %d", elements["text"], tt.wantTexts)
+			}
+			if gotGrid := strings.Contains(got, "stroke-opacity"); gotGrid != tt.wantGrid {
+				t.Errorf("SVG() grid = %v, want %v", gotGrid, tt.wantGrid)
+			}
+			soft := Palettes["soft"]
+			for _, fill := range []string{hexColor(soft.Pieces['L'][0]), hexColor(soft.Pieces['I'][0]), hexColor(soft.Empty)} {
+				if !strings.Contains(got, fill) {
+					t.Errorf("SVG() lacks fill %s", fill)
+				}
+			}
+		})
+	}
+}
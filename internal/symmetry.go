@@ -0,0 +1,186 @@
+// Package internal canonicalizes solved boards so equivalent tilings can be deduplicated.
+package internal
+
+// Symmetry identifies one of the 8 symmetries of the square (rotations and reflections).
+type Symmetry int
+
+const (
+	Identity      Symmetry = iota // (r, c) -> (r, c)
+	Rotate90                      // Quarter turn clockwise
+	Rotate180                     // Half turn
+	Rotate270                     // Quarter turn counter-clockwise
+	FlipH                         // Mirror left-right
+	FlipV                         // Mirror top-bottom
+	Transpose                     // Mirror across the main diagonal
+	AntiTranspose                 // Mirror across the anti-diagonal
+)
+
+// Symmetries lists all 8 square symmetries, Identity first.
+var Symmetries = []Symmetry{Identity, Rotate90, Rotate180, Rotate270, FlipH, FlipV, Transpose, AntiTranspose}
+
+// apply maps a cell of an n×n square to its position under the symmetry.
+func (s Symmetry) apply(p Point, n int) Point {
+	r, c, m := p.Row, p.Col, n-1 // m is the last valid index
+	switch s {                   // Each symmetry as a coordinate map
+	case Rotate90:
+		return Point{Row: c, Col: m - r}
+	case Rotate180:
+		return Point{Row: m - r, Col: m - c}
+	case Rotate270:
+		return Point{Row: m - c, Col: r}
+	case FlipH:
+		return Point{Row: r, Col: m - c}
+	case FlipV:
+		return Point{Row: m - r, Col: c}
+	case Transpose:
+		return Point{Row: c, Col: r}
+	case AntiTranspose:
+		return Point{Row: m - c, Col: m - r}
+	}
+	return p // Identity
+}
+
+// transformShape returns the shape ID of coords after applying the symmetry.
+func (s Symmetry) transformShape(coords []Point) int {
+	moved := make([]Point, len(coords)) // Transformed cells (4×4 frame is large enough for any tetromino)
+	for i, p := range coords {          // Every cell of the shape
+		moved[i] = s.apply(p, 4) // Its image in a 4×4 frame
+	}
+	return ShapeID(moved) // ShapeID normalizes the translation away
+}
+
+// Transform returns a copy of the board with the symmetry applied to every cell.
+func (b *Board) Transform(s Symmetry) *Board {
+	out := NewBoard(b.Size)      // Same size: the board is square
+	for r, row := range b.Grid { // Iterate through each row
+		for c, cell := range row { // Iterate through each cell
+			q := s.apply(Point{Row: r, Col: c}, b.Size) // Destination cell
+			out.Grid[q.Row][q.Col] = cell               // Copy the cell to its image
+		}
+	}
+	return out
+}
+
+// PermittedSymmetries returns the symmetries that map the piece multiset onto itself.
+// Pieces cannot be rotated, so a rotated tiling only solves the same puzzle when
+// every transformed shape is matched by an input piece of that shape.
+func PermittedSymmetries(pieces []*Tetromino) []Symmetry {
+	counts := shapeCounts(pieces) // Shape ID -> number of pieces with that shape
+
+	var permitted []Symmetry       // Symmetries that keep the multiset
+	for _, s := range Symmetries { // Try every symmetry
+		moved := make(map[int]int, len(counts)) // Shape counts after transformation
+		for id, n := range counts {             // Every shape present
+			moved[s.transformShape(CanonicalShapes[id])] += n // Its image takes its count
+		}
+		if countsEqual(counts, moved) { // Multiset preserved
+			permitted = append(permitted, s)
+		}
+	}
+	return permitted
+}
+
+// Canonical returns the representative of the board's equivalence class: the
+// lexicographically smallest String() over all permitted symmetries, with
+// identical pieces relabelled in row-major order of first appearance.
+// Two solutions are the same tiling exactly when their canonical boards are equal.
+func Canonical(b *Board, pieces []*Tetromino) *Board {
+	groups := shapeLabels(pieces) // Shape ID -> labels available for that shape
+
+	var best *Board                                 // Smallest board so far
+	bestKey := ""                                   // Its rendering
+	for _, s := range PermittedSymmetries(pieces) { // Every allowed symmetry
+		candidate := relabel(b.Transform(s), groups)                 // Transformed and relabelled
+		if key := candidate.String(); best == nil || key < bestKey { // Keep smallest rendering
+			best, bestKey = candidate, key
+		}
+	}
+	return best
+}
+
+// Unique filters solutions down to one per equivalence class, keeping the first
+// board seen from each class and preserving input order.
+func Unique(boards []*Board, pieces []*Tetromino) []*Board {
+	first := firstOfClass(pieces) // Seen-class tracker
+	var unique []*Board           // Kept boards, in input order
+	for _, b := range boards {    // Boards in input order
+		if first(b) { // First of its class
+			unique = append(unique, b)
+		}
+	}
+	return unique
+}
+
+// firstOfClass returns a function reporting whether a board is the first it has
+// been shown from its equivalence class. Only the canonical keys are kept.
+func firstOfClass(pieces []*Tetromino) func(*Board) bool {
+	seen := make(map[string]bool) // Canonical renderings already shown
+	return func(b *Board) bool {
+		key := Canonical(b, pieces).String() // Class representative
+		if seen[key] {                       // Equivalent to an earlier board
+			return false
+		}
+		seen[key] = true // Remember the class
+		return true
+	}
+}
+
+// relabel renames pieces so that identical shapes take their group's labels in
+// row-major order of first appearance.
+func relabel(b *Board, groups map[int][]byte) *Board {
+	cells, order := b.labelCells() // Cells and labels in row-major order
+
+	next := make(map[int]int)     // Shape ID -> index of next unused label in its group
+	rename := make(map[byte]byte) // Old label -> new label
+	for _, label := range order { // Labels in order of first appearance
+		id := ShapeID(cells[label])                     // Shape of this piece
+		if group := groups[id]; next[id] < len(group) { // Label available for this shape
+			rename[label] = group[next[id]]
+			next[id]++ // Next label of the group
+		} else { // Shape not in the piece set (board is not a solution); keep label
+			rename[label] = label
+		}
+	}
+
+	out := b.Copy()                // Relabelled copy
+	for r, row := range out.Grid { // Iterate through each row
+		for c, cell := range row { // Iterate through each cell
+			if cell != '.' { // Occupied cell
+				out.Grid[r][c] = rename[cell]
+			}
+		}
+	}
+	return out
+}
+
+// shapeCounts counts pieces per shape ID.
+func shapeCounts(pieces []*Tetromino) map[int]int {
+	counts := make(map[int]int) // Shape ID -> number of pieces
+	for _, p := range pieces {  // Count every piece
+		counts[ShapeID(p.Coords)]++
+	}
+	return counts
+}
+
+// shapeLabels groups piece labels by shape ID, in input order.
+func shapeLabels(pieces []*Tetromino) map[int][]byte {
+	groups := make(map[int][]byte) // Shape ID -> labels
+	for _, p := range pieces {     // Every piece, in input order
+		id := ShapeID(p.Coords)                  // Its shape
+		groups[id] = append(groups[id], p.Label) // Label joins its group
+	}
+	return groups
+}
+
+// countsEqual reports whether two shape multisets are identical.
+func countsEqual(a, b map[int]int) bool {
+	if len(a) != len(b) { // Different number of distinct shapes
+		return false
+	}
+	for id, n := range a { // Every shape of a
+		if b[id] != n { // Count mismatch for this shape
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBoardTransform(t *testing.T) {
+	b := boardFromRows("AB", "CD")
+
+	tests := []struct {
+		sym  Symmetry
+		want string
+	}{
+		{Identity, "AB\nCD\n"},
+		{Rotate90, "CA\nDB\n"},
+		{Rotate180, "DC\nBA\n"},
+		{Rotate270, "BD\nAC\n"},
+		{FlipH, "BA\nDC\n"},
+		{FlipV, "CD\nAB\n"},
+		{Transpose, "AC\nBD\n"},
+		{AntiTranspose, "DB\nCA\n"},
+	}
+
+	for _, tt := range tests {
+		if got := b.Transform(tt.sym).String(); got != tt.want {
+			t.Errorf("Transform(%d) = %q, want %q", tt.sym, got, tt.want)
+		}
+	}
+}
+
+// TestPermittedSymmetries checks that only symmetries preserving the shape multiset are allowed.
+func TestPermittedSymmetries(t *testing.T) {
+	o := []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	tDown := []Point{{0, 0}, {0, 1}, {0, 2}, {1, 1}}
+
+	tests := []struct {
+		name   string
+		pieces []*Tetromino
+		want   []Symmetry
+	}{
+		{"O piece", []*Tetromino{{Label: 'A', Coords: o}}, Symmetries},
+		{"T piece", []*Tetromino{{Label: 'A', Coords: tDown}}, []Symmetry{Identity, FlipH}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PermittedSymmetries(tt.pieces)
+			if len(got) != len(tt.want) {
+				t.Fatalf("PermittedSymmetries() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("PermittedSymmetries() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestCanonical_MirroredSolutions verifies that a mirror image with swapped S/Z labels is recognised.
+func TestCanonical_MirroredSolutions(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 1}, {0, 2}, {1, 0}, {1, 1}}}, // S horizontal
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 1}, {1, 2}}}, // Z horizontal
+	}
+
+	original := boardFromRows(".AA.", "AA..", "BB..", ".BB.")
+	mirrored := boardFromRows(".BB.", "..BB", "..AA", ".AA.")
+	distinct := boardFromRows("..AA", ".AA.", "BB..", ".BB.")
+
+	if Canonical(original, pieces).String() != Canonical(mirrored, pieces).String() {
+		t.Errorf("Canonical() differs for mirrored solutions:\n%s\n%s", original, mirrored)
+	}
+	if Canonical(original, pieces).String() == Canonical(distinct, pieces).String() {
+		t.Errorf("Canonical() equal for distinct solutions:\n%s\n%s", original, distinct)
+	}
+
+	unique := Unique([]*Board{original, mirrored, distinct}, pieces)
+	if len(unique) != 2 {
+		t.Fatalf("Unique() returned %d boards, want 2", len(unique))
+	}
+	if unique[0] != original || unique[1] != distinct {
+		t.Error("Unique() should keep the first board of each class in input order")
+	}
+}
+
+// TestUnique_IdenticalPieces checks that relabelling identical pieces collapses permutations.
+func TestUnique_IdenticalPieces(t *testing.T) {
+	pieces := make([]*Tetromino, 4)
+	for i := range pieces {
+		pieces[i] = &Tetromino{Label: byte('A' + i), Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}}
+	}
+
+	result := SolveAll(context.Background(), pieces)
+	if result.Timeout {
+		t.Fatal("SolveAll() timed out")
+	}
+	if len(result.Solutions) != 24 { // 4! label permutations of the single 2×2 quadrant tiling
+		t.Errorf("SolveAll() found %d solutions, want 24", len(result.Solutions))
+	}
+
+	unique := Unique(result.Solutions, pieces)
+	if len(unique) != 1 {
+		t.Errorf("Unique() returned %d boards, want 1", len(unique))
+	}
+
+	streamed := SolveAllUnique(context.Background(), pieces)
+	if len(streamed.Solutions) != 1 || streamed.Board != streamed.Solutions[0] {
+		t.Fatalf("SolveAllUnique() found %d solutions, want 1", len(streamed.Solutions))
+	}
+	if streamed.Board.String() != unique[0].String() {
+		t.Errorf("SolveAllUnique() = %q, want the board Unique() keeps, %q", streamed.Board, unique[0])
+	}
+}
+
+// boardFromRows builds a board from equal-length row strings.
+func boardFromRows(rows ...string) *Board {
+	b := NewBoard(len(rows))
+	for r, row := range rows {
+		copy(b.Grid[r], row)
+	}
+	return b
+}
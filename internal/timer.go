@@ -2,9 +2,13 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -18,6 +22,14 @@ const (
 type Timer struct {
 	start time.Time // When timer was created
 	isTTY bool      // Whether stderr is a terminal
+	out   io.Writer // Where progress is drawn (stderr)
+	lines int       // Lines of the last live display, erased by the next draw
+	width int       // Width of the last progress line, blanked by the next
+
+	liveAt    time.Time // When the last live display was drawn (zero before the first)
+	liveTotal int64     // Nodes searched by then; the node rate is measured from here
+
+	phases []Phase // Timed parts of the run, in the order first added
 }
 
 // NewTimer creates a new Timer instance.
@@ -25,12 +37,18 @@ func NewTimer() *Timer {
 	return &Timer{
 		start: time.Now(), // Record start time
 		isTTY: isTTY(),    // Detect terminal
+		out:   os.Stderr,  // Progress goes to stderr, keeping stdout clean
 	}
 }
 
 // isTTY checks if stderr is a terminal.
 func isTTY() bool {
-	fi, err := os.Stderr.Stat() // Get stderr file info
+	return IsTerminal(os.Stderr) // Progress is drawn on stderr
+}
+
+// IsTerminal checks if f is a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat() // Get file info
 	if err != nil {
 		return false
 	}
@@ -61,37 +79,140 @@ func (t *Timer) IsTimedOut() bool {
 	return t.Elapsed() >= Timeout // Compare elapsed to timeout
 }
 
-// AddDuration is a no-op kept for compatibility.
-func (t *Timer) AddDuration(name string, d time.Duration) {}
+// Phase is a named part of a run and the time spent in it.
+type Phase struct {
+	Name     string        // What was done, e.g. "parse" or "search"
+	Size     int           // Board size worked on, 0 for phases not tied to one
+	Duration time.Duration // Total time spent
+}
 
-// ShowProgress displays the progress bar if TTY is available.
-func (t *Timer) ShowProgress() {
-	if !t.isTTY { // Skip if not a terminal
-		return
+// label names p in the timings table, e.g. "search size 7".
+func (p Phase) label() string {
+	if p.Size > 0 { // Tied to a board size
+		return fmt.Sprintf("%s size %d", p.Name, p.Size)
 	}
+	return p.Name
+}
 
-	remaining := t.Remaining()               // Get time remaining
-	minutes := int(remaining.Minutes())      // Extract minutes
-	seconds := int(remaining.Seconds()) % 60 // Extract seconds component
+// AddDuration adds d to the phase called name.
+func (t *Timer) AddDuration(name string, d time.Duration) {
+	t.AddPhase(Phase{Name: name, Duration: d}) // A phase without a size
+}
 
-	elapsed := t.Elapsed()                          // Get elapsed time
-	progress := float64(elapsed) / float64(Timeout) // Calculate progress ratio
-	if progress > 1.0 {                             // Clamp to 1.0
-		progress = 1.0
+// AddPhase adds p.Duration to the phase with p's name and size, which is
+// created after the existing ones the first time.
+func (t *Timer) AddPhase(p Phase) {
+	for i := range t.phases { // Look for the same phase
+		if t.phases[i].Name == p.Name && t.phases[i].Size == p.Size { // Seen before: accumulate
+			t.phases[i].Duration += p.Duration
+			return
+		}
 	}
+	t.phases = append(t.phases, p) // New phase goes last
+}
 
-	filled := int(progress * ProgressWidth) // Calculate filled bar width
-	empty := ProgressWidth - filled         // Calculate empty bar width
+// Phases returns the phases in the order they were first added.
+func (t *Timer) Phases() []Phase {
+	return append([]Phase(nil), t.phases...) // Copy: callers cannot reorder ours
+}
+
+// WriteTimings writes a table of the phases with their share of the time
+// elapsed, then the time outside every phase ("other") and the total.
+func (t *Timer) WriteTimings(w io.Writer) error {
+	total := t.Elapsed()                                                                                              // Get total elapsed time
+	rows := append(t.Phases(), Phase{Name: "other", Duration: t.other(total)}, Phase{Name: "total", Duration: total}) // Phases, then the remainder and the total
 
-	bar := ""                     // Build progress bar string
-	for i := 0; i < filled; i++ { // Add filled characters
-		bar += "█"
+	width := len("phase")    // Width of the first column
+	for _, p := range rows { // Widest label
+		width = max(width, len(p.label()))
+	}
+	var sb strings.Builder                                               // Table accumulator
+	fmt.Fprintf(&sb, "%-*s %12s %7s\n", width, "phase", "time", "share") // Header row
+	for _, p := range rows {                                             // One row per phase
+		share := 0.0   // Share of the total
+		if total > 0 { // Avoid dividing by zero
+			share = 100 * float64(p.Duration) / float64(total)
+		}
+		fmt.Fprintf(&sb, "%-*s %9.3f ms %6.1f%%\n", width, p.label(), float64(p.Duration)/float64(time.Millisecond), share) // Milliseconds and percentage
 	}
-	for i := 0; i < empty; i++ { // Add empty characters
-		bar += "░"
+	_, err := io.WriteString(w, sb.String()) // One write for the whole table
+	return err
+}
+
+// timingsJSON is the JSON form of the timings; times are in seconds.
+type timingsJSON struct {
+	Phases []phaseJSON `json:"phases"`
+	Other  float64     `json:"other_seconds"`
+	Total  float64     `json:"total_seconds"`
+}
+
+// phaseJSON is one phase in timingsJSON.
+type phaseJSON struct {
+	Name    string  `json:"name"`
+	Size    int     `json:"size,omitempty"`
+	Seconds float64 `json:"seconds"`
+}
+
+// WriteTimingsJSON writes the timings of WriteTimings as a JSON object on one
+// line, e.g. {"phases":[{"name":"parse","seconds":0.0002}],"other_seconds":0.001,"total_seconds":0.0012}.
+func (t *Timer) WriteTimingsJSON(w io.Writer) error {
+	total := t.Elapsed()                                                                               // Get total elapsed time
+	out := timingsJSON{Phases: []phaseJSON{}, Other: t.other(total).Seconds(), Total: total.Seconds()} // Empty list rather than null
+	for _, p := range t.phases {                                                                       // Every phase, in order
+		out.Phases = append(out.Phases, phaseJSON{Name: p.Name, Size: p.Size, Seconds: p.Duration.Seconds()}) // Seconds per phase
 	}
+	return json.NewEncoder(w).Encode(out) // One line of JSON
+}
 
-	fmt.Fprintf(os.Stderr, "\rSolving... timeout in: %02d:%02d [%s]", minutes, seconds, bar) // Display progress
+// other returns the part of total spent outside every phase.
+func (t *Timer) other(total time.Duration) time.Duration {
+	for _, p := range t.phases { // Subtract every phase
+		total -= p.Duration
+	}
+	return max(total, 0) // Phases measured by their own clocks may add up to a little more
+}
+
+// ShowProgress displays the progress bar if TTY is available. While snap has
+// an estimate (see ProgressSnapshot.ETA), the bar shows how much of the current
+// size's search is done and when it should end; otherwise it fills up towards
+// the timeout.
+func (t *Timer) ShowProgress(snap ProgressSnapshot) {
+	if !t.isTTY { // Skip if not a terminal
+		return
+	}
+
+	remaining := t.Remaining()     // Get time remaining
+	var line string                // Status line being built
+	if eta, ok := snap.ETA(); ok { // Search progress known
+		line = fmt.Sprintf("Solving size %d... %3.0f%% [%s] ETA %s", snap.Size, 100*snap.Explored, bar(snap.Explored), clock(eta))
+		if eta > remaining { // Will not finish this size in time
+			line += " (after timeout)"
+		}
+	} else { // No estimate: time towards the timeout
+		elapsed := float64(t.Elapsed()) / float64(Timeout) // Calculate progress ratio
+		line = fmt.Sprintf("Solving... timeout in: %s [%s]", clock(remaining), bar(elapsed))
+	}
+
+	width := utf8.RuneCountInString(line)                          // Columns on screen, not bytes
+	fmt.Fprintf(t.out, "\r%s%*s", line, max(t.width-width, 0), "") // Display progress, blanking the rest of a longer line
+	t.width = width                                                // Remember for the next draw
+}
+
+// bar draws a progress bar ProgressWidth wide that is filled to fraction f.
+func bar(f float64) string {
+	f = min(max(f, 0), 1)                                           // Clamp to [0, 1]
+	filled := int(f * ProgressWidth)                                // Calculate filled bar width
+	empty := ProgressWidth - filled                                 // Calculate empty bar width
+	return strings.Repeat("█", filled) + strings.Repeat("░", empty) // Filled, then empty cells
+}
+
+// clock formats d as MM:SS, or H:MM:SS from an hour up.
+func clock(d time.Duration) string {
+	secs := int(d.Seconds()) // Whole seconds
+	if secs >= 3600 {        // An hour or more
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
 }
 
 // ClearProgress clears the progress line if TTY is available.
@@ -99,7 +220,60 @@ func (t *Timer) ClearProgress() {
 	if !t.isTTY { // Skip if not a terminal
 		return
 	}
-	fmt.Fprintf(os.Stderr, "\r%*s\r", 31+ProgressWidth, "") // Overwrite with spaces (31 = prefix length)
+	if t.lines > 0 { // Live display: erase its lines
+		t.erase() // Cursor back over the display
+		return
+	}
+	fmt.Fprintf(t.out, "\r%*s\r", t.width, "") // Overwrite with spaces
+	t.width = 0                                // Nothing left to blank
+}
+
+// ShowLive redraws the live display if TTY is available: the board size being
+// tried, the depth reached, the node rate since the previous drawing, the
+// estimate if any and the partial board in snap (coloured if color is set),
+// replacing the previous drawing.
+func (t *Timer) ShowLive(snap ProgressSnapshot, color bool) {
+	if !t.isTTY { // Skip if not a terminal
+		return
+	}
+
+	now, since := time.Now(), t.liveAt // Rate is measured since the last drawing
+	if since.IsZero() {                // First drawing: measure from the start
+		since = t.start
+	}
+	rate := float64(snap.Total-t.liveTotal) / now.Sub(since).Seconds() // Current, not the run's average
+	t.liveAt, t.liveTotal = now, snap.Total                            // Start of the next measurement
+
+	var sb strings.Builder // Display accumulator
+	if snap.Size == 0 {    // Search not started yet
+		sb.WriteString("Solving...")
+	} else { // Search running
+		fmt.Fprintf(&sb, "Solving size %d: depth %d (max %d), %d nodes, %.0f nodes/s",
+			snap.Size, snap.Depth, snap.MaxDepth, snap.Total, rate)
+	}
+	if eta, ok := snap.ETA(); ok { // Search progress known
+		fmt.Fprintf(&sb, ", %.0f%% explored, ETA %s", 100*snap.Explored, clock(eta))
+	}
+	fmt.Fprintf(&sb, ", timeout in: %s\n", clock(t.Remaining())) // Time left before the timeout
+	if snap.Board != nil {                                       // SAT backend has no partial board
+		if color { // Colour asked for
+			sb.WriteString(snap.Board.ColorString())
+		} else { // Plain letters
+			sb.WriteString(snap.Board.String())
+		}
+	}
+
+	t.erase()                                  // Remove the previous drawing
+	io.WriteString(t.out, sb.String())         // Draw the new one
+	t.lines = strings.Count(sb.String(), "\n") // Erased by the next draw
+}
+
+// erase moves the cursor back over the last live display and clears it.
+func (t *Timer) erase() {
+	if t.lines > 0 { // Something is on screen
+		fmt.Fprintf(t.out, "\r\x1b[%dA\x1b[J", t.lines) // Cursor up, then clear to end of screen
+	}
+	t.lines = 0 // Nothing left to erase
 }
 
 // ShowCompletion displays completion message to stderr if TTY.
@@ -107,6 +281,6 @@ func (t *Timer) ShowCompletion(solveTime time.Duration) {
 	if !t.isTTY { // Skip if not a terminal
 		return
 	}
-	totalTime := t.Elapsed()                                         // Get total elapsed time
-	fmt.Fprintf(os.Stderr, "Solved in %.2fs\n", totalTime.Seconds()) // Display completion message
+	totalTime := t.Elapsed()                                     // Get total elapsed time
+	fmt.Fprintf(t.out, "Solved in %.2fs\n", totalTime.Seconds()) // Display completion message
 }
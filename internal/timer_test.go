@@ -1,8 +1,12 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestNewTimer(t *testing.T) {
@@ -53,7 +57,7 @@ func TestTimerShowProgress_NoTTY(t *testing.T) {
 	tmr.isTTY = false
 
 	// Should not panic when not a TTY
-	tmr.ShowProgress()
+	tmr.ShowProgress(ProgressSnapshot{})
 }
 
 func TestTimerClearProgress_NoTTY(t *testing.T) {
@@ -64,6 +68,187 @@ func TestTimerClearProgress_NoTTY(t *testing.T) {
 	tmr.ClearProgress()
 }
 
+// TestTimerShowProgress checks the bar follows the estimate when there is one.
+func TestTimerShowProgress(t *testing.T) {
+	var out bytes.Buffer
+	tmr := NewTimer()
+	tmr.isTTY, tmr.out = true, &out
+	started := time.Now().Add(-10 * time.Second)
+
+	tests := []struct {
+		name string
+		snap ProgressSnapshot
+		want []string
+	}{
+		{"no estimate", ProgressSnapshot{Size: 7, Explored: -1}, []string{"timeout in: 0", "[░░░"}},
+		{"estimate", ProgressSnapshot{Size: 7, Explored: 0.5, Started: started}, []string{"size 7", " 50% [██████████░░░░░░░░░░] ETA 00:1"}},
+		{"after timeout", ProgressSnapshot{Size: 8, Explored: 0.001, Started: started}, []string{"ETA 2:46:", "(after timeout)"}},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		tmr.ShowProgress(tt.snap)
+		got := out.String()
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: ShowProgress() = %q, want it to contain %q", tt.name, got, want)
+			}
+		}
+	}
+
+	last := utf8.RuneCountInString(out.String())
+	out.Reset()
+	tmr.ShowProgress(ProgressSnapshot{Size: 8, Explored: 0.5, Started: started}) // Shorter than the last line
+	got := out.String()
+	if utf8.RuneCountInString(got) != last || !strings.HasSuffix(got, "  ") {
+		t.Errorf("ShowProgress() = %q, want the rest of the longer line blanked", got)
+	}
+	shown := utf8.RuneCountInString(strings.TrimSpace(got)) // The padding is blank already
+
+	out.Reset()
+	tmr.ClearProgress()
+	if got, want := out.String(), "\r"+strings.Repeat(" ", shown)+"\r"; got != want {
+		t.Errorf("ClearProgress() = %q, want %q", got, want)
+	}
+}
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{59*time.Second + 900*time.Millisecond, "00:59"},
+		{5 * time.Minute, "05:00"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+	for _, tt := range tests {
+		if got := clock(tt.d); got != tt.want {
+			t.Errorf("clock(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+// TestTimerPhases checks phases accumulate by name and size, in first-seen order.
+func TestTimerPhases(t *testing.T) {
+	tmr := NewTimer()
+	tmr.AddDuration("parse", time.Millisecond)
+	tmr.AddPhase(Phase{Name: "search", Size: 5, Duration: 2 * time.Millisecond})
+	tmr.AddPhase(Phase{Name: "search", Size: 6, Duration: 3 * time.Millisecond})
+	tmr.AddPhase(Phase{Name: "search", Size: 5, Duration: 4 * time.Millisecond})
+	tmr.AddDuration("render", time.Millisecond)
+	tmr.AddDuration("render", time.Millisecond)
+
+	want := []Phase{
+		{Name: "parse", Duration: time.Millisecond},
+		{Name: "search", Size: 5, Duration: 6 * time.Millisecond},
+		{Name: "search", Size: 6, Duration: 3 * time.Millisecond},
+		{Name: "render", Duration: 2 * time.Millisecond},
+	}
+	got := tmr.Phases()
+	if len(got) != len(want) {
+		t.Fatalf("Phases() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Phases()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestTimerWriteTimings checks the table and JSON forms, including the time left over.
+func TestTimerWriteTimings(t *testing.T) {
+	tmr := NewTimer()
+	tmr.start = time.Now().Add(-100 * time.Millisecond)
+	tmr.AddDuration("parse", 10*time.Millisecond)
+	tmr.AddPhase(Phase{Name: "search", Size: 7, Duration: 50 * time.Millisecond})
+
+	var out bytes.Buffer
+	if err := tmr.WriteTimings(&out); err != nil {
+		t.Fatalf("WriteTimings() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	wantRows := []string{"phase", "parse ", "search size 7 ", "other ", "total "}
+	if len(lines) != len(wantRows) {
+		t.Fatalf("WriteTimings() =\n%s\nwant rows %q", out.String(), wantRows)
+	}
+	for i, row := range wantRows {
+		if !strings.HasPrefix(lines[i], row) {
+			t.Errorf("WriteTimings() line %d = %q, want it to start with %q", i, lines[i], row)
+		}
+	}
+	if !strings.Contains(lines[1], "10.000 ms") || !strings.Contains(lines[2], "50.000 ms") || !strings.HasSuffix(lines[4], "100.0%") {
+		t.Errorf("WriteTimings() =\n%s\nwant parse 10 ms, search 50 ms, total 100%%", out.String())
+	}
+
+	out.Reset()
+	if err := tmr.WriteTimingsJSON(&out); err != nil {
+		t.Fatalf("WriteTimingsJSON() error: %v", err)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("WriteTimingsJSON() = %q, want one line", out.String())
+	}
+	var got timingsJSON
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("WriteTimingsJSON() = %q: %v", out.String(), err)
+	}
+	want := []phaseJSON{{Name: "parse", Seconds: 0.01}, {Name: "search", Size: 7, Seconds: 0.05}}
+	if len(got.Phases) != 2 || got.Phases[0] != want[0] || got.Phases[1] != want[1] {
+		t.Errorf("WriteTimingsJSON() phases = %+v, want %+v", got.Phases, want)
+	}
+	if got.Total < 0.1 || got.Other < 0.04 || got.Other > got.Total-0.06+1e-9 {
+		t.Errorf("WriteTimingsJSON() other %v, total %v; want total >= 0.1 and other = total - 0.06", got.Other, got.Total)
+	}
+}
+
+// TestTimerShowLive checks the live display and that each draw erases the last.
+func TestTimerShowLive(t *testing.T) {
+	var out bytes.Buffer
+	tmr := NewTimer()
+	tmr.isTTY, tmr.out = true, &out
+
+	b, err := ParseBoard(strings.NewReader("AA..\nAA..\n....\n....\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+	snap := ProgressSnapshot{Size: 4, Depth: 1, MaxDepth: 3, Nodes: 10, Total: 25, Board: b}
+
+	tmr.ShowLive(snap, false)
+	first := out.String()
+	for _, want := range []string{"size 4", "depth 1 (max 3)", "25 nodes", b.String()} {
+		if !strings.Contains(first, want) {
+			t.Errorf("ShowLive() = %q, want it to contain %q", first, want)
+		}
+	}
+	if strings.Contains(first, "\x1b[") {
+		t.Errorf("first ShowLive() = %q, want nothing to erase", first)
+	}
+
+	out.Reset()
+	tmr.ShowLive(snap, true)
+	if got := out.String(); !strings.HasPrefix(got, "\r\x1b[5A\x1b[J") || !strings.Contains(got, b.ColorString()) {
+		t.Errorf("second ShowLive() = %q, want the 5 earlier lines erased and a coloured board", got)
+	}
+
+	out.Reset()
+	tmr.liveAt, tmr.liveTotal = time.Now().Add(-10*time.Second), snap.Total // 5000 nodes in the last 10s
+	tmr.ShowLive(ProgressSnapshot{Size: 4, Total: snap.Total + 5000}, false)
+	if got := out.String(); !strings.Contains(got, " 500 nodes/s") {
+		t.Errorf("ShowLive() = %q, want the rate since the previous drawing, 500 nodes/s", got)
+	}
+
+	out.Reset()
+	tmr.ShowLive(ProgressSnapshot{Size: 5}, false) // SAT: no board
+	if got := out.String(); strings.Count(got, "\n") != 1 {
+		t.Errorf("ShowLive() without a board = %q, want one line", got)
+	}
+
+	out.Reset()
+	tmr.ClearProgress()
+	if got := out.String(); got != "\r\x1b[1A\x1b[J" {
+		t.Errorf("ClearProgress() = %q, want the live line erased", got)
+	}
+}
+
 func TestTimerShowCompletion_NoTTY(t *testing.T) {
 	tmr := NewTimer()
 	tmr.isTTY = false
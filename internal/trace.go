@@ -0,0 +1,197 @@
+// Package internal replays search logs step by step for display.
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TraceEvent is one step of a search log (see Options.Log).
+type TraceEvent struct {
+	Kind  string // "size", "place", "undo", or an outcome: "solved", "exhausted" or "cancelled"
+	Size  int    // Board size (size events)
+	Label byte   // Piece placed or removed (place and undo events)
+	Row   int    // Position of the piece (place events)
+	Col   int
+	Nodes int64 // Placements tried at this size (outcome events)
+}
+
+func (e TraceEvent) String() string {
+	switch e.Kind { // Describe the event for display
+	case "size": // New board
+		return fmt.Sprintf("size %d", e.Size)
+	case "place": // Piece added
+		return fmt.Sprintf("place %c at %d,%d", e.Label, e.Row, e.Col)
+	case "undo": // Piece removed
+		return fmt.Sprintf("undo %c", e.Label)
+	}
+	return fmt.Sprintf("%s after %d nodes", e.Kind, e.Nodes)
+}
+
+// Replay reads a search log line by line and plays it on a board: a size line
+// starts an empty board, place and undo lines add and remove pieces. fn is
+// called after every event with the board as it stands; returning false stops
+// the replay. Unlike CheckLog it does not need the log to cover a whole search
+// tree, and it streams, so logs of any length can be replayed.
+func Replay(r io.Reader, pieces []*Tetromino, fn func(TraceEvent, *Board) bool) error {
+	byLabel := make(map[byte]*Tetromino, len(pieces)) // Label -> piece
+	for _, p := range pieces {                        // Index every piece
+		byLabel[p.Label] = p
+	}
+
+	scanner := bufio.NewScanner(r)          // Streams the log a line at a time
+	var b *Board                            // Board being replayed, nil before the first size
+	var placed map[byte]bool                // Labels on b
+	for line := 1; scanner.Scan(); line++ { // Count lines, comments included
+		text := strings.TrimSpace(scanner.Text())       // Ignore surrounding whitespace
+		if text == "" || strings.HasPrefix(text, "#") { // Blank lines and comments
+			continue
+		}
+		ev, err := parseTraceEvent(strings.Fields(text)) // Kind and arguments
+		if err != nil {                                  // Malformed line
+			return &LogError{Message: err.Error(), Line: line}
+		}
+
+		if ev.Kind != "size" && b == nil { // Events before the first size line
+			return &LogError{Message: "expected size line", Line: line}
+		}
+		switch ev.Kind { // Apply the event to the board
+		case "size": // New board size
+			if err := checkLogSize(ev.Size, pieces); err != nil { // Before NewBoard allocates it
+				return &LogError{Message: err.Error(), Line: line}
+			}
+			b, placed = NewBoard(ev.Size), make(map[byte]bool) // Empty board, nothing placed
+		case "place": // Piece added
+			p := byLabel[ev.Label]                                              // Piece with this label
+			if p == nil || placed[ev.Label] || !b.CanPlace(p, ev.Row, ev.Col) { // Unknown, already placed, or illegal
+				return &LogError{Message: fmt.Sprintf("cannot place %c at %d,%d", ev.Label, ev.Row, ev.Col), Line: line}
+			}
+			b.Place(p, ev.Row, ev.Col) // Put it on the board
+			placed[ev.Label] = true    // Now on the board
+		case "undo": // Piece removed
+			if !placed[ev.Label] { // Not on the board
+				return &LogError{Message: fmt.Sprintf("piece %c is not on the board", ev.Label), Line: line}
+			}
+			for _, row := range b.Grid { // Iterate through each row
+				for i, cell := range row { // Iterate through each cell
+					if cell == ev.Label { // Cell of the removed piece
+						row[i] = '.' // Clear it
+					}
+				}
+			}
+			placed[ev.Label] = false // Off the board
+		}
+		if !fn(ev, b) { // Caller asked to stop
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil { // Log broke off rather than ended
+		return fmt.Errorf("reading search log: %w", err)
+	}
+	return nil
+}
+
+// parseTraceEvent parses the fields of one search log line.
+func parseTraceEvent(fields []string) (TraceEvent, error) {
+	ev := TraceEvent{Kind: fields[0]} // Kind is the first field
+	var err error
+	switch { // Arguments by kind
+	case ev.Kind == "size" && len(fields) == 2: // Board size
+		ev.Size, err = strconv.Atoi(fields[1]) // Parse the size
+		if err == nil && ev.Size < 0 {         // Number, but negative
+			err = fmt.Errorf("negative size")
+		}
+	case ev.Kind == "place" && len(fields) == 4 && len(fields[1]) == 1: // Label and position
+		ev.Label = fields[1][0]                                // One-letter label
+		if ev.Row, err = strconv.Atoi(fields[2]); err == nil { // Row, then column
+			ev.Col, err = strconv.Atoi(fields[3])
+		}
+	case ev.Kind == "undo" && len(fields) == 2 && len(fields[1]) == 1: // Label only
+		ev.Label = fields[1][0] // One-letter label
+	case (ev.Kind == "solved" || ev.Kind == "exhausted" || ev.Kind == "cancelled") && len(fields) == 2: // Outcome with its node count
+		ev.Nodes, err = strconv.ParseInt(fields[1], 10, 64) // Parse the count
+	default: // Anything else
+		return ev, fmt.Errorf("unrecognised line %q", strings.Join(fields, " "))
+	}
+	if err != nil { // An argument failed to parse
+		return ev, fmt.Errorf("invalid line %q", strings.Join(fields, " "))
+	}
+	return ev, nil
+}
+
+// MaxGIFFrames caps the frames of a GIFRecorder; an animation of more frames
+// would take too long to watch and too much memory to build.
+const MaxGIFFrames = 5000
+
+// GIFRecorder collects boards into an animated GIF.
+type GIFRecorder struct {
+	opts    ImageOptions
+	delay   int // Per frame, in hundredths of a second
+	palette color.Palette
+	anim    gif.GIF
+}
+
+// NewGIFRecorder returns a recorder drawing frames with opts (see Board.Image)
+// and showing each for delay hundredths of a second. Grid lines are not drawn:
+// their blended colours are not in the GIF's palette.
+func NewGIFRecorder(opts ImageOptions, delay int) *GIFRecorder {
+	opts = opts.withDefaults()                                                                          // Fill in unset fields
+	opts.Grid = false                                                                                   // Frames would need blended colours
+	pal := color.Palette{color.Transparent, opts.Palette.Empty, opts.Palette.Border, opts.Palette.Text} // Fixed colours first
+	families := make([]byte, 0, len(opts.Palette.Pieces))                                               // Families that have colours
+	for family := range opts.Palette.Pieces {                                                           // Collect the families
+		families = append(families, family)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i] < families[j] }) // Map order would change the GIF from run to run
+	for _, family := range families {                                              // Two shades per family
+		shades := opts.Palette.Pieces[family]
+		pal = append(pal, shades[0], shades[1])
+	}
+	return &GIFRecorder{opts: opts, delay: delay, palette: pal}
+}
+
+// Add appends a frame showing b. Returns an error once MaxGIFFrames is reached.
+func (g *GIFRecorder) Add(b *Board) error {
+	if len(g.anim.Image) >= MaxGIFFrames {
+		return fmt.Errorf("animation exceeds %d frames", MaxGIFFrames)
+	}
+	img := b.Image(g.opts)                                         // Full-colour frame
+	frame := image.NewPaletted(img.Bounds(), g.palette)            // Same size, palette colours
+	draw.Draw(frame, frame.Bounds(), img, image.Point{}, draw.Src) // Every colour is in the palette: an exact copy
+	g.anim.Image = append(g.anim.Image, frame)                     // Append the frame
+	g.anim.Delay = append(g.anim.Delay, g.delay)                   // And its delay
+	return nil
+}
+
+// Frames returns the number of frames added so far.
+func (g *GIFRecorder) Frames() int {
+	return len(g.anim.Image)
+}
+
+// Encode writes the animation, holding the last frame for two seconds. The
+// canvas fits the largest board; smaller boards sit in its top-left corner.
+func (g *GIFRecorder) Encode(w io.Writer) error {
+	if len(g.anim.Image) == 0 { // Nothing to encode
+		return fmt.Errorf("animation has no frames")
+	}
+	anim := g.anim                                                          // Copy of the animation
+	anim.Delay = append([]int{}, g.anim.Delay...)                           // Copy: the last delay is changed below
+	anim.Delay[len(anim.Delay)-1] = max(anim.Delay[len(anim.Delay)-1], 200) // Hold the last frame
+	anim.Disposal = make([]byte, len(anim.Image))                           // One disposal per frame
+	for i, frame := range anim.Image {                                      // Every frame
+		anim.Disposal[i] = gif.DisposalBackground                         // A smaller board must not leave a larger one showing
+		anim.Config.Width = max(anim.Config.Width, frame.Bounds().Dx())   // Canvas fits the widest board
+		anim.Config.Height = max(anim.Config.Height, frame.Bounds().Dy()) // And the tallest
+	}
+	anim.Config.ColorModel = g.palette // Palette for the whole animation
+	anim.BackgroundIndex = 0           // Transparent
+	return gif.EncodeAll(w, &anim)     // Write every frame
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/gif"
+	"strings"
+	"testing"
+)
+
+// TestReplay checks that replaying a search log rebuilds the solver's boards.
+func TestReplay(t *testing.T) {
+	pieces := parsePiecesFromString(t, hardExample)[:6]
+	var log bytes.Buffer
+	result := SolveWith(context.Background(), pieces, Options{Log: &log})
+
+	var last *Board
+	kinds := map[string]int{}
+	err := Replay(strings.NewReader(log.String()), pieces, func(ev TraceEvent, b *Board) bool {
+		kinds[ev.Kind]++
+		last = b.Copy()
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+
+	if last.String() != result.Board.String() {
+		t.Errorf("final board:\n%s\nwant:\n%s", last, result.Board)
+	}
+	var nodes int64
+	for _, r := range result.Sizes {
+		nodes += r.Nodes
+	}
+	if int64(kinds["place"]) != nodes {
+		t.Errorf("replayed %d placements, want %d", kinds["place"], nodes)
+	}
+	if kinds["size"] != kinds["exhausted"]+kinds["solved"] || kinds["solved"] != 1 {
+		t.Errorf("event counts = %v, want one outcome per size and one solution", kinds)
+	}
+}
+
+// TestReplay_Stop checks that fn can end the replay early.
+func TestReplay_Stop(t *testing.T) {
+	log := "size 3\nplace A 0 0\nundo A\nplace A 0 1\n"
+	calls := 0
+	err := Replay(strings.NewReader(log), logPieces, func(TraceEvent, *Board) bool {
+		calls++
+		return calls < 2
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("Replay() = %v after %d calls, want nil after 2", err, calls)
+	}
+}
+
+// TestReplay_Errors checks that logs not matching the puzzle are rejected with their line.
+func TestReplay_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		wantLine int
+	}{
+		{"no size", "place A 0 0\n", 1},
+		{"unknown piece", "size 3\nplace Q 0 0\n", 2},
+		{"off the board", "size 3\nplace A 1 0\n", 2},
+		{"overlap", "size 4\nplace A 0 0\nplace B 0 0\n", 3},
+		{"placed twice", "size 5\nplace A 0 0\nplace A 0 2\n", 3},
+		{"undo of absent piece", "# comment\nsize 3\nundo A\n", 3},
+		{"garbage", "size 3\nmove A\n", 2},
+		{"bad number", "size 3\nplace A x 0\n", 2},
+		{"size too small", "size 2\n", 1},
+		{"size too large", "# comment\nsize 1000000\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Replay(strings.NewReader(tt.log), logPieces, func(TraceEvent, *Board) bool { return true })
+			var lerr *LogError
+			if !errors.As(err, &lerr) {
+				t.Fatalf("Replay() error = %v, want *LogError", err)
+			}
+			if lerr.Line != tt.wantLine {
+				t.Errorf("error line = %d, want %d (%v)", lerr.Line, tt.wantLine, err)
+			}
+		})
+	}
+}
+
+// TestGIFRecorder checks frame layout, timing and the frame cap.
+func TestGIFRecorder(t *testing.T) {
+	small, large := NewBoard(2), NewBoard(3)
+	rec := NewGIFRecorder(ImageOptions{CellSize: 8}, 5)
+	for _, b := range []*Board{small, small, large} {
+		if err := rec.Add(b); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rec.Encode(&buf); err != nil {
+		t.Fatalf("Encode() error: %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error: %v", err)
+	}
+	if len(anim.Image) != 3 || rec.Frames() != 3 {
+		t.Fatalf("GIF has %d frames, want 3", len(anim.Image))
+	}
+	if anim.Config.Width != 3*8+2 || anim.Config.Height != 3*8+2 {
+		t.Errorf("canvas = %dx%d, want the largest board", anim.Config.Width, anim.Config.Height)
+	}
+	if anim.Delay[0] != 5 || anim.Delay[2] != 200 {
+		t.Errorf("delays = %v, want 5 and a held last frame", anim.Delay)
+	}
+
+	capped := NewGIFRecorder(ImageOptions{CellSize: 4}, 5)
+	for i := 0; i < MaxGIFFrames; i++ {
+		if err := capped.Add(small); err != nil {
+			t.Fatalf("Add() #%d error: %v", i, err)
+		}
+	}
+	if err := capped.Add(small); err == nil {
+		t.Error("Add() past MaxGIFFrames succeeded, want error")
+	}
+}
+
+// TestGIFRecorder_Deterministic checks that the same boards always encode to the same bytes.
+func TestGIFRecorder_Deterministic(t *testing.T) {
+	b, err := ParseBoard(strings.NewReader("ABBBB\nACCC.\nA..C.\nADD..\nDD...\n"))
+	if err != nil {
+		t.Fatalf("ParseBoard() error: %v", err)
+	}
+	var first []byte
+	for i := 0; i < 10; i++ { // Enough runs for a map-ordered palette to show up
+		rec := NewGIFRecorder(ImageOptions{CellSize: 4}, 5)
+		if err := rec.Add(b); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := rec.Encode(&buf); err != nil {
+			t.Fatalf("Encode() error: %v", err)
+		}
+		if i == 0 {
+			first = buf.Bytes()
+		} else if !bytes.Equal(buf.Bytes(), first) {
+			t.Fatalf("encoding #%d differs from the first", i+1)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package internal checks claimed solutions against the input pieces.
+package internal
+
+import (
+	"context"
+	"fmt"
+)
+
+// VerifyError describes why a claimed solution is not valid.
+type VerifyError struct {
+	Message string
+	Label   byte // Piece label the error refers to, 0 if not piece-specific
+}
+
+func (e *VerifyError) Error() string {
+	if e.Label != 0 { // Error is associated with a specific piece
+		return fmt.Sprintf("%s for piece %c", e.Message, e.Label)
+	}
+	return e.Message
+}
+
+// Verify checks that the board is a valid placement of the pieces: the board is
+// square, contains only '.' and the pieces' labels, and each label's cells form
+// exactly that piece's shape at some translation.
+func Verify(pieces []*Tetromino, b *Board) error {
+	if b == nil { // Nothing to check
+		return &VerifyError{Message: "no board"}
+	}
+	if len(b.Grid) != b.Size { // Row count must match declared size
+		return &VerifyError{Message: fmt.Sprintf("board has %d rows (expected %d)", len(b.Grid), b.Size)}
+	}
+
+	shapes := make(map[byte][]Point, len(pieces)) // Label -> expected normalized shape
+	for _, p := range pieces {                    // Every input piece
+		shapes[p.Label] = Normalize(p.Coords) // Shape at the origin
+	}
+
+	for r, row := range b.Grid { // Iterate through each row
+		if len(row) != b.Size { // Board must be square
+			return &VerifyError{Message: fmt.Sprintf("row %d has %d cells (expected %d)", r+1, len(row), b.Size)}
+		}
+		for c, cell := range row { // Iterate through each cell
+			if _, ok := shapes[cell]; !ok && cell != '.' { // Not '.' and not one of the input labels
+				return &VerifyError{Message: fmt.Sprintf("unexpected character '%c' at row %d, column %d", cell, r+1, c+1)}
+			}
+		}
+	}
+
+	cells, _ := b.labelCells() // Label -> cells found on the board
+
+	for _, p := range pieces { // Every input piece
+		found := cells[p.Label] // Its cells on the board
+		if len(found) == 0 {    // Piece was never placed
+			return &VerifyError{Message: "no cells", Label: p.Label}
+		}
+		if !pointsEqual(Normalize(found), shapes[p.Label]) { // Wrong shape, rotated, or extra cells
+			return &VerifyError{Message: "cells do not match input shape", Label: p.Label}
+		}
+	}
+
+	return nil
+}
+
+// VerifyMinimal runs Verify and additionally proves that the board size is the
+// smallest possible, by exhaustively searching the next smaller size.
+// Returns ctx.Err() if the proof could not be completed in time.
+func VerifyMinimal(ctx context.Context, pieces []*Tetromino, b *Board) error {
+	if err := Verify(pieces, b); err != nil { // Placement first
+		return err
+	}
+
+	smaller := b.Size - 1 // One size below the board
+	if smaller < 0 {      // Nothing is smaller than an empty board
+		return nil
+	}
+
+	report := SolveSize(ctx, pieces, smaller, Options{}).Sizes[0] // Exhaust the smaller size
+	switch report.Outcome {                                       // What the smaller size proved
+	case Solved: // Pieces fit in a smaller square
+		return &VerifyError{Message: fmt.Sprintf("not minimal: pieces fit in a %dx%d board", smaller, smaller)}
+	case Cancelled: // Search stopped before exhausting the smaller size
+		return ctx.Err()
+	case Failed: // Search broke down; nothing proven
+		return report.Err
+	}
+	return nil
+}
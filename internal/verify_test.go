@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVerifyError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *VerifyError
+		wantStr string
+	}{
+		{"without label", &VerifyError{Message: "no board"}, "no board"},
+		{"with label", &VerifyError{Message: "no cells", Label: 'C'}, "no cells for piece C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.wantStr {
+				t.Errorf("Error() = %q, want %q", got, tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}}, // O
+		{Label: 'B', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {3, 0}}}, // I vertical
+	}
+
+	tests := []struct {
+		name    string
+		board   *Board
+		wantErr bool
+	}{
+		{"valid", boardFromRows("AAB.", "AAB.", "..B.", "..B."), false},
+		{"valid with translation", boardFromRows("...B", "AA.B", "AA.B", "...B"), false},
+		{"nil board", nil, true},
+		{"not square", &Board{Grid: [][]byte{[]byte("AAB"), []byte("AAB"), []byte("..B"), []byte("..B")}, Size: 4}, true},
+		{"row count mismatch", &Board{Grid: [][]byte{[]byte("AAB.")}, Size: 4}, true},
+		{"extra label", boardFromRows("AAB.", "AAB.", "..B.", "..BC"), true},
+		{"invalid character", boardFromRows("AAB.", "AAB.", "..B.", "..B#"), true},
+		{"missing piece", boardFromRows("AA..", "AA..", "....", "...."), true},
+		{"rotated piece", boardFromRows("AA..", "AA..", "BBBB", "...."), true},
+		{"extra cell", boardFromRows("AAB.", "AAB.", "A.B.", "..B."), true},
+		{"split piece", boardFromRows("AA.B", "AAB.", "..B.", "..B."), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(pieces, tt.board)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var verr *VerifyError
+			if err != nil && !errors.As(err, &verr) {
+				t.Errorf("Verify() error type = %T, want *VerifyError", err)
+			}
+		})
+	}
+}
+
+func TestVerifyMinimal(t *testing.T) {
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+	ctx := context.Background()
+
+	if err := VerifyMinimal(ctx, pieces, boardFromRows("AA", "AA")); err != nil {
+		t.Errorf("VerifyMinimal(2x2) error = %v, want nil", err)
+	}
+	if err := VerifyMinimal(ctx, pieces, boardFromRows("AA.", "AA.", "...")); err == nil {
+		t.Error("VerifyMinimal(3x3) should reject non-minimal board")
+	}
+
+	// Two O pieces need 3x3 (8 cells fit in 9) but cannot fit it; 4x4 is minimal
+	two := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{Label: 'B', Coords: []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+	if err := VerifyMinimal(ctx, two, boardFromRows("AABB", "AABB", "....", "....")); err != nil {
+		t.Errorf("VerifyMinimal(two O) error = %v, want nil", err)
+	}
+}
+
+func TestVerifyMinimal_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// L and J cannot share a 3x3 board, but no cheap bound shows it; a search is needed
+	pieces := []*Tetromino{
+		{Label: 'A', Coords: []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}}},
+		{Label: 'B', Coords: []Point{{0, 1}, {1, 1}, {2, 0}, {2, 1}}},
+	}
+	board := boardFromRows("A..B", "A..B", "AABB", "....")
+	if CheckBounds(pieces, 3) != nil {
+		t.Fatal("test needs a size that bounds cannot rule out")
+	}
+
+	err := VerifyMinimal(ctx, pieces, board)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("VerifyMinimal() error = %v, want context.Canceled", err)
+	}
+	if err := VerifyMinimal(context.Background(), pieces, board); err != nil {
+		t.Errorf("VerifyMinimal() error = %v, want nil", err)
+	}
+}